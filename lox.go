@@ -2,11 +2,13 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"loxlang/parser"
 	"loxlang/parser/def"
 	"loxlang/parser/lexer"
+	"loxlang/parser/module"
 	"loxlang/parser/pass"
 	"loxlang/parser/runtime"
 	"os"
@@ -14,23 +16,26 @@ import (
 )
 
 func main() {
-	args := os.Args[0:]
+	modulePath := flag.String("path", "", "directories where modules are searched, after LOXPATH ones")
+	flag.Parse()
+	args := flag.Args()
 	fmt.Println()
-	if len(args) > 2 {
-		fmt.Println("Usage: lox [script]")
-	} else if len(args) == 2 {
-		runFile(args[1])
+	if len(args) > 1 {
+		fmt.Println("Usage: lox [-path dirs] [script]")
+	} else if len(args) == 1 {
+		runFile(args[0], module.SearchPath(*modulePath))
 	} else {
 		runPrompt()
 	}
 }
 
-func runFile(filePath string) {
+func runFile(filePath string, searchPath []string) {
 	dat, err := ioutil.ReadFile(filePath)
 	if err != nil {
 		panic(err)
 	}
-	run(string(dat))
+	def.CurrentFile = filePath
+	run(string(dat), filePath, searchPath)
 	if def.HadError {
 		os.Exit(65)
 	}
@@ -64,11 +69,20 @@ func runPrompt() {
 		interpreter.Interpret(stmts)
 		def.HadError = false
 	}
+	closeGenerators(&interpreter)
 }
 
-func run(content string) {
+// closeGenerators lets the generators left paused run their finally blocks before exiting
+func closeGenerators(interpreter *runtime.Interpreter) {
+	err := interpreter.CloseGenerators()
+	if err != nil {
+		def.ReportRuntimeError(err)
+	}
+}
+
+func run(content string, filePath string, searchPath []string) {
 	// lexer
-	tokens := lexer.ScanTokens(content)
+	tokens := lexer.ScanFile(content, filePath)
 
 	if def.HadError {
 		def.HadError = false
@@ -83,6 +97,7 @@ func run(content string) {
 	}
 
 	interpreter := runtime.NewInterpreter()
+	interpreter.Loader = module.NewLoader(filePath, searchPath)
 
 	// static analyses
 	resolver := pass.NewResolver(*interpreter)
@@ -94,5 +109,6 @@ func run(content string) {
 	}
 	// runtime
 	interpreter.Interpret(stmts)
+	closeGenerators(interpreter)
 
 }
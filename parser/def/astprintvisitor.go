@@ -11,6 +11,20 @@ type StrVisitor interface {
 	VisitLogicalExprStr(logical *Logical) string
 	VisitCallExpr(call *Call) string
 	VisitFunctionExpr(fnExpr *FunctionExpr) string
+	VisitGetExprStr(get *Get) string
+	VisitOptionalChainExprStr(chain *OptionalChain) string
+	VisitSetExprStr(set *Set) string
+	VisitThisExprStr(this *This) string
+	VisitSuperExprStr(super *Super) string
+	VisitListLiteralExprStr(list *ListLiteral) string
+	VisitTupleExprStr(tuple *Tuple) string
+	VisitIndexExprStr(index *Index) string
+	VisitIndexSetExprStr(indexSet *IndexSet) string
+	VisitMapLiteralExprStr(mapLiteral *MapLiteral) string
+	VisitInterpolationExprStr(interpolation *Interpolation) string
+	VisitCompoundAssignExprStr(compound *CompoundAssign) string
+	VisitIncrementExprStr(increment *Increment) string
+	VisitConditionalExprStr(conditional *Conditional) string
 }
 
 // AcceptStr def for type
@@ -62,3 +76,73 @@ func (call *Call) AcceptStr(v StrVisitor) string {
 func (fnExpr *FunctionExpr) AcceptStr(v StrVisitor) string {
 	return v.VisitFunctionExpr(fnExpr)
 }
+
+// AcceptStr def for type
+func (get *Get) AcceptStr(v StrVisitor) string {
+	return v.VisitGetExprStr(get)
+}
+
+// AcceptStr def for type
+func (chain *OptionalChain) AcceptStr(v StrVisitor) string {
+	return v.VisitOptionalChainExprStr(chain)
+}
+
+// AcceptStr def for type
+func (set *Set) AcceptStr(v StrVisitor) string {
+	return v.VisitSetExprStr(set)
+}
+
+// AcceptStr def for type
+func (this *This) AcceptStr(v StrVisitor) string {
+	return v.VisitThisExprStr(this)
+}
+
+// AcceptStr def for type
+func (super *Super) AcceptStr(v StrVisitor) string {
+	return v.VisitSuperExprStr(super)
+}
+
+// AcceptStr def for type
+func (list *ListLiteral) AcceptStr(v StrVisitor) string {
+	return v.VisitListLiteralExprStr(list)
+}
+
+// AcceptStr def for type
+func (tuple *Tuple) AcceptStr(v StrVisitor) string {
+	return v.VisitTupleExprStr(tuple)
+}
+
+// AcceptStr def for type
+func (index *Index) AcceptStr(v StrVisitor) string {
+	return v.VisitIndexExprStr(index)
+}
+
+// AcceptStr def for type
+func (indexSet *IndexSet) AcceptStr(v StrVisitor) string {
+	return v.VisitIndexSetExprStr(indexSet)
+}
+
+// AcceptStr def for type
+func (mapLiteral *MapLiteral) AcceptStr(v StrVisitor) string {
+	return v.VisitMapLiteralExprStr(mapLiteral)
+}
+
+// AcceptStr def for type
+func (interpolation *Interpolation) AcceptStr(v StrVisitor) string {
+	return v.VisitInterpolationExprStr(interpolation)
+}
+
+// AcceptStr def for type
+func (compound *CompoundAssign) AcceptStr(v StrVisitor) string {
+	return v.VisitCompoundAssignExprStr(compound)
+}
+
+// AcceptStr def for type
+func (increment *Increment) AcceptStr(v StrVisitor) string {
+	return v.VisitIncrementExprStr(increment)
+}
+
+// AcceptStr def for type
+func (conditional *Conditional) AcceptStr(v StrVisitor) string {
+	return v.VisitConditionalExprStr(conditional)
+}
@@ -11,15 +11,21 @@ type RuntimeError struct {
 	Message string
 	Type    ErrorType
 	Value   interface{}
+	// Label is the loop a break or continue belongs to, empty for the innermost one
+	Label string
 }
 
 func (err *RuntimeError) Error() string {
-	return fmt.Sprintf("[line=%d] Error: %s", err.Token.Line, err.Message)
+	return fmt.Sprintf("%s Error: %s", location(err.Token.File, err.Token.Line), err.Message)
 }
 
 // Error Types
 const (
-	NORMAL           ErrorType = 0
-	CONTROLFLOWBREAK ErrorType = 1
-	RETURNSTMT       ErrorType = 2
+	NORMAL              ErrorType = 0
+	CONTROLFLOWBREAK    ErrorType = 1
+	RETURNSTMT          ErrorType = 2
+	CONTROLFLOWCONTINUE ErrorType = 3
+	THROWN              ErrorType = 4
+	GENERATORCLOSED     ErrorType = 5
+	OPTIONALNIL         ErrorType = 6
 )
@@ -11,16 +11,31 @@ var HadError bool = false
 // HadRuntimeError - Runtime errors
 var HadRuntimeError bool = false
 
+// CurrentFile - File being scanned, parsed or resolved, shown by compile errors
+var CurrentFile string = ""
+
 // LogError - Logs error
 func LogError(line int, message string) {
 	HadError = true
-	fmt.Printf("[line=%d] Error: %s\n", line, message)
+	fmt.Printf("%s Error: %s\n", location(CurrentFile, line), message)
 }
 
 // Report - Log error with more info
 func Report(line int, where string, message string) {
 	HadError = true
-	fmt.Printf("[line=%d] Error %s: %s\n", line, where, message)
+	fmt.Printf("%s Error %s: %s\n", location(CurrentFile, line), where, message)
+}
+
+// Warning - Logs a problem that doesn't stop the program
+func Warning(line int, message string) {
+	fmt.Printf("%s Warning: %s\n", location(CurrentFile, line), message)
+}
+
+func location(file string, line int) string {
+	if file == "" {
+		return fmt.Sprintf("[line=%d]", line)
+	}
+	return fmt.Sprintf("[file=%s line=%d]", file, line)
 }
 
 // ReportRuntimeError Reports Runtime errors
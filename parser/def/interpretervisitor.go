@@ -11,6 +11,20 @@ type ExpressionVisitor interface {
 	VisitLogicalExpr(logical *Logical) (interface{}, *RuntimeError)
 	VisitCallExpr(call *Call) (interface{}, *RuntimeError)
 	VisitFunctionExpr(fnExpr *FunctionExpr) (interface{}, *RuntimeError)
+	VisitGetExpr(get *Get) (interface{}, *RuntimeError)
+	VisitOptionalChainExpr(chain *OptionalChain) (interface{}, *RuntimeError)
+	VisitSetExpr(set *Set) (interface{}, *RuntimeError)
+	VisitThisExpr(this *This) (interface{}, *RuntimeError)
+	VisitSuperExpr(super *Super) (interface{}, *RuntimeError)
+	VisitListLiteralExpr(list *ListLiteral) (interface{}, *RuntimeError)
+	VisitTupleExpr(tuple *Tuple) (interface{}, *RuntimeError)
+	VisitIndexExpr(index *Index) (interface{}, *RuntimeError)
+	VisitIndexSetExpr(indexSet *IndexSet) (interface{}, *RuntimeError)
+	VisitMapLiteralExpr(mapLiteral *MapLiteral) (interface{}, *RuntimeError)
+	VisitInterpolationExpr(interpolation *Interpolation) (interface{}, *RuntimeError)
+	VisitCompoundAssignExpr(compound *CompoundAssign) (interface{}, *RuntimeError)
+	VisitIncrementExpr(increment *Increment) (interface{}, *RuntimeError)
+	VisitConditionalExpr(conditional *Conditional) (interface{}, *RuntimeError)
 }
 
 // StatementVisitor Interface
@@ -18,12 +32,21 @@ type StatementVisitor interface {
 	VisitExpressionStmt(exprStmt *ExprStmt) *RuntimeError
 	VisitPrintStmt(print *Print) *RuntimeError
 	VisitVar(varStmt *Var) *RuntimeError
+	VisitDestructure(destructure *Destructure) *RuntimeError
 	VisitBlock(block *Block) *RuntimeError
 	VisitIf(ifStmt *If) *RuntimeError
 	VisitWhile(whileStmt *While) *RuntimeError
+	VisitForIn(forIn *ForIn) *RuntimeError
 	VisitControlFlow(controlFlow *ControlFlow) *RuntimeError
 	VisitFunction(function *Function) *RuntimeError
 	VisitReturnStmt(returnStmt *Return) *RuntimeError
+	VisitYield(yield *Yield) *RuntimeError
+	VisitClass(class *Class) *RuntimeError
+	VisitEnum(enum *Enum) *RuntimeError
+	VisitThrow(throw *Throw) *RuntimeError
+	VisitTry(try *Try) *RuntimeError
+	VisitImport(importStmt *Import) *RuntimeError
+	VisitMatch(match *Match) *RuntimeError
 }
 
 /*Expression and Statement Accepts */
@@ -63,6 +86,26 @@ func (returnStmt *Return) Accept(v StatementVisitor) *RuntimeError {
 	return v.VisitReturnStmt(returnStmt)
 }
 
+// Accept def for type
+func (forIn *ForIn) Accept(v StatementVisitor) *RuntimeError {
+	return v.VisitForIn(forIn)
+}
+
+// Accept def for type
+func (yield *Yield) Accept(v StatementVisitor) *RuntimeError {
+	return v.VisitYield(yield)
+}
+
+// Accept def for type
+func (destructure *Destructure) Accept(v StatementVisitor) *RuntimeError {
+	return v.VisitDestructure(destructure)
+}
+
+// Accept def for type
+func (enum *Enum) Accept(v StatementVisitor) *RuntimeError {
+	return v.VisitEnum(enum)
+}
+
 // Accept def for type
 func (controlFlow *ControlFlow) Accept(v StatementVisitor) *RuntimeError {
 	return v.VisitControlFlow(controlFlow)
@@ -73,6 +116,31 @@ func (function *Function) Accept(v StatementVisitor) *RuntimeError {
 	return v.VisitFunction(function)
 }
 
+// Accept def for type
+func (class *Class) Accept(v StatementVisitor) *RuntimeError {
+	return v.VisitClass(class)
+}
+
+// Accept def for type
+func (throw *Throw) Accept(v StatementVisitor) *RuntimeError {
+	return v.VisitThrow(throw)
+}
+
+// Accept def for type
+func (try *Try) Accept(v StatementVisitor) *RuntimeError {
+	return v.VisitTry(try)
+}
+
+// Accept def for type
+func (importStmt *Import) Accept(v StatementVisitor) *RuntimeError {
+	return v.VisitImport(importStmt)
+}
+
+// Accept def for type
+func (match *Match) Accept(v StatementVisitor) *RuntimeError {
+	return v.VisitMatch(match)
+}
+
 // Accept def for type
 func (empty *EmptyExpr) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
 	return "", nil
@@ -122,3 +190,73 @@ func (call *Call) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
 func (fnExpr *FunctionExpr) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
 	return v.VisitFunctionExpr(fnExpr)
 }
+
+// Accept def for type
+func (get *Get) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitGetExpr(get)
+}
+
+// Accept def for type
+func (chain *OptionalChain) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitOptionalChainExpr(chain)
+}
+
+// Accept def for type
+func (set *Set) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitSetExpr(set)
+}
+
+// Accept def for type
+func (this *This) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitThisExpr(this)
+}
+
+// Accept def for type
+func (super *Super) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitSuperExpr(super)
+}
+
+// Accept def for type
+func (list *ListLiteral) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitListLiteralExpr(list)
+}
+
+// Accept def for type
+func (tuple *Tuple) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitTupleExpr(tuple)
+}
+
+// Accept def for type
+func (index *Index) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitIndexExpr(index)
+}
+
+// Accept def for type
+func (indexSet *IndexSet) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitIndexSetExpr(indexSet)
+}
+
+// Accept def for type
+func (mapLiteral *MapLiteral) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitMapLiteralExpr(mapLiteral)
+}
+
+// Accept def for type
+func (interpolation *Interpolation) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitInterpolationExpr(interpolation)
+}
+
+// Accept def for type
+func (compound *CompoundAssign) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitCompoundAssignExpr(compound)
+}
+
+// Accept def for type
+func (increment *Increment) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitIncrementExpr(increment)
+}
+
+// Accept def for type
+func (conditional *Conditional) Accept(v ExpressionVisitor) (interface{}, *RuntimeError) {
+	return v.VisitConditionalExpr(conditional)
+}
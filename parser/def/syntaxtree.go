@@ -20,6 +20,16 @@ type Block struct {
 type Var struct {
 	Name        Token
 	Initializer Expr
+	// Const is set for const and let, which can't be assigned again
+	Const bool
+}
+
+// Destructure declares many variables from a tuple or a list, like var (head, ...tail) = f();
+type Destructure struct {
+	Keyword     Token
+	Names       []Token
+	Rest        *Token
+	Initializer Expr
 }
 
 // Return returns a value from inside a function
@@ -28,9 +38,16 @@ type Return struct {
 	Value   Expr
 }
 
+// Yield hands a value to the caller of a generator, pausing it until the next value is asked
+type Yield struct {
+	Keyword Token
+	Value   Expr
+}
+
 // Print is a simple Print statement for the language
 type Print struct {
-	Expr Expr
+	Keyword Token
+	Expr    Expr
 }
 
 // If represents conditional if statements
@@ -46,15 +63,109 @@ type Function struct {
 	FuncExpr FunctionExpr
 }
 
-// While represents repetition loop
+// Enum represents an enum declaration, like enum Color { Red, Green, Blue }
+type Enum struct {
+	Name    Token
+	Members []Token
+}
+
+// Class represents a class declaration with its methods
+type Class struct {
+	Name       Token
+	Superclass *Variable
+	Methods    []*Function
+}
+
+// While represents repetition loop, Increment is only set by for loops
 type While struct {
 	Condition Expr
 	Body      Stmt
+	Increment Expr
+	// Do is set for do-while, where the body runs before the first check
+	Do    bool
+	Label *Token
+}
+
+// ForIn represents a loop over the values of an iterable, like for (var x in xs)
+type ForIn struct {
+	Keyword  Token
+	Name     Token
+	Iterable Expr
+	Body     Stmt
+	Label    *Token
 }
 
 // ControlFlow represents break or continue
 type ControlFlow struct {
-	Type ErrorType
+	Keyword Token
+	Type    ErrorType
+	// Label names the loop to leave, nil means the innermost one
+	Label *Token
+}
+
+// Throw raises an exception with any value
+type Throw struct {
+	Keyword Token
+	Value   Expr
+}
+
+// Try represents try/catch/finally, CatchBody and FinallyBody are nil when missing
+type Try struct {
+	Body        []Stmt
+	CatchName   Token
+	CatchBody   []Stmt
+	FinallyBody []Stmt
+}
+
+// Import brings a module in, either whole under Alias or only the listed Names
+type Import struct {
+	Keyword Token
+	Path    Token
+	Alias   Token
+	Names   []Token
+}
+
+// Match represents a match statement, only the first case that matches runs
+type Match struct {
+	Keyword Token
+	Subject Expr
+	Cases   []*MatchCase
+}
+
+// MatchCase is one arm of a match, it runs Body when any of its Patterns matches and Guard holds
+type MatchCase struct {
+	Keyword  Token
+	Patterns []*Pattern
+	Guard    Expr
+	Body     Stmt
+}
+
+// PatternKind identifies the kind of a match pattern
+type PatternKind int8
+
+// Pattern kinds
+const (
+	PATTERNLITERAL PatternKind = iota
+	PATTERNRANGE
+	PATTERNWILDCARD
+	PATTERNBIND
+	PATTERNLIST
+	PATTERNMAP
+	PATTERNMEMBER
+)
+
+// Pattern represents a match pattern, fields are used depending on Kind:
+// Value for literals, Value and High for inclusive ranges, Token for bound names,
+// Elements and Rest for lists, Keys and Elements for maps, Member for qualified names like Color.Red
+type Pattern struct {
+	Kind     PatternKind
+	Token    Token
+	Value    interface{}
+	High     interface{}
+	Keys     []interface{}
+	Elements []*Pattern
+	Rest     *Token
+	Member   *Get
 }
 
 // Expr Mostly generic Tree Node
@@ -70,7 +181,12 @@ type EmptyExpr struct {
 // FunctionExpr represents an anonymous function declaration
 type FunctionExpr struct {
 	Params []Token
-	Body   []Stmt
+	// Defaults has one entry per param, nil when the param is required
+	Defaults []Expr
+	Rest     *Token
+	Body     []Stmt
+	// IsGenerator is set when the body yields, calling it returns a generator
+	IsGenerator bool
 }
 
 // Literal represents literal values like "abc", 13, 15.6
@@ -85,9 +201,16 @@ type Grouping struct {
 
 // Call represents a function call
 type Call struct {
-	Callee    Expr
-	Paren     Token
-	Arguments []Expr
+	Callee         Expr
+	Paren          Token
+	Arguments      []Expr
+	NamedArguments []*NamedArgument
+}
+
+// NamedArgument represents an argument passed by name, like b: 3
+type NamedArgument struct {
+	Name  Token
+	Value Expr
 }
 
 // Binary represents expressions with two expr and one operator, like 1 + 2, a > b
@@ -97,7 +220,7 @@ type Binary struct {
 	Right Expr
 }
 
-// Logical represents 'and' and 'or' expressions
+// Logical represents 'and', 'or' and '??' expressions
 type Logical struct {
 	Left     Expr
 	Operator Token
@@ -120,3 +243,96 @@ type Assign struct {
 	Name  Token
 	Value Expr
 }
+
+// Get represents a property access, like obj.field, Optional is set for obj?.field
+type Get struct {
+	Object   Expr
+	Name     Token
+	Optional bool
+}
+
+// OptionalChain wraps a chain of accesses with a ?., the whole chain is nil once a ?. sees nil
+type OptionalChain struct {
+	Expr Expr
+}
+
+// Set represents a property assign, like obj.field = value
+type Set struct {
+	Object Expr
+	Name   Token
+	Value  Expr
+}
+
+// This represents the 'this' keyword inside methods
+type This struct {
+	Keyword Token
+}
+
+// Super represents a superclass method access, like super.method
+type Super struct {
+	Keyword Token
+	Method  Token
+}
+
+// ListLiteral represents a list literal, like [1, 2, 3]
+type ListLiteral struct {
+	Bracket  Token
+	Elements []Expr
+}
+
+// Tuple represents a group of values, like the ones in return a, b;
+type Tuple struct {
+	Token    Token
+	Elements []Expr
+}
+
+// Index represents an index access, like xs[i]
+type Index struct {
+	Object  Expr
+	Bracket Token
+	Index   Expr
+}
+
+// IndexSet represents an index assign, like xs[i] = value
+type IndexSet struct {
+	Object  Expr
+	Bracket Token
+	Index   Expr
+	Value   Expr
+}
+
+// MapLiteral represents a map literal, like {"a": 1, "b": 2}
+type MapLiteral struct {
+	Brace  Token
+	Keys   []Expr
+	Values []Expr
+}
+
+// Interpolation represents a string with embedded expressions, like "total: ${a + b}"
+type Interpolation struct {
+	Token Token
+	Parts []Expr
+}
+
+// CompoundAssign represents assigns like a += 1, Operator is the arithmetic operator applied.
+// Target is a Variable, a Get or an Index, and it is evaluated only once
+type CompoundAssign struct {
+	Target   Expr
+	Operator Token
+	Value    Expr
+}
+
+// Increment represents ++ and --, both prefix and postfix
+type Increment struct {
+	Target   Expr
+	Operator Token
+	Prefix   bool
+}
+
+// Conditional represents the ternary operator, like cond ? a : b
+type Conditional struct {
+	Condition Expr
+	Question  Token
+	Then      Expr
+	Else      Expr
+}
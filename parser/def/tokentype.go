@@ -10,13 +10,21 @@ const (
 	RIGHTPAREN
 	LEFTBRACE
 	RIGHTBRACE
+	LEFTBRACKET
+	RIGHTBRACKET
 	COMMA
+	COLON
 	DOT
 	MINUS
 	PLUS
 	SEMICOLON
 	SLASH
 	STAR
+	PERCENT
+	AMPERSAND
+	PIPE
+	CARET
+	TILDE
 
 	// One or two character tokens.
 	BANG
@@ -27,11 +35,29 @@ const (
 	GREATEREQUAL
 	LESS
 	LESSEQUAL
+	STARSTAR
+	TILDESLASH
+	LESSLESS
+	GREATERGREATER
+	PLUSEQUAL
+	MINUSEQUAL
+	STAREQUAL
+	SLASHEQUAL
+	PERCENTEQUAL
+	PLUSPLUS
+	MINUSMINUS
+	QUESTION
+	QUESTIONQUESTION
+	QUESTIONDOT
+	ARROW
+	DOTDOT
+	ELLIPSIS
 
 	// Literals.
 	IDENTIFIER
 	STRING
 	NUMBER
+	INTERPOLATION
 
 	// Keywords.
 	AND
@@ -52,33 +78,62 @@ const (
 	WHILE
 	EOF
 	BREAK
+	CONTINUE
+	THROW
+	TRY
+	CATCH
+	FINALLY
+	IMPORT
+	MATCH
+	CASE
+	CONST
+	LET
+	IN
+	YIELD
+	DO
+	ENUM
 )
 
 // Keywords of the language
 var Keywords map[string]TokenType = map[string]TokenType{
-	"and":    AND,
-	"class":  CLASS,
-	"else":   ELSE,
-	"false":  FALSE,
-	"for":    FOR,
-	"fun":    FUN,
-	"if":     IF,
-	"nil":    NIL,
-	"or":     OR,
-	"print":  PRINT,
-	"return": RETURN,
-	"super":  SUPER,
-	"this":   THIS,
-	"true":   TRUE,
-	"var":    VAR,
-	"while":  WHILE,
-	"break":  BREAK,
+	"and":      AND,
+	"class":    CLASS,
+	"else":     ELSE,
+	"false":    FALSE,
+	"for":      FOR,
+	"fun":      FUN,
+	"if":       IF,
+	"nil":      NIL,
+	"or":       OR,
+	"print":    PRINT,
+	"return":   RETURN,
+	"super":    SUPER,
+	"this":     THIS,
+	"true":     TRUE,
+	"var":      VAR,
+	"while":    WHILE,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"throw":    THROW,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"import":   IMPORT,
+	"match":    MATCH,
+	"case":     CASE,
+	"const":    CONST,
+	"let":      LET,
+	"in":       IN,
+	"yield":    YIELD,
+	"do":       DO,
+	"enum":     ENUM,
 }
 
-// Token simples agroups TOken related values
+// Token simples agroups TOken related values, File is empty when the source isn't a file
 type Token struct {
 	Type    TokenType
 	Lexeme  string
 	Literal interface{}
 	Line    int
+	File    string
 }
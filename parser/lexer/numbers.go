@@ -0,0 +1,153 @@
+package lexer
+
+import (
+	"fmt"
+	"loxlang/parser/def"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// processNumber handles decimal numbers, with fraction and exponent, and 0x, 0b, 0o prefixed integers.
+// Every form accepts '_' between digits as separator. Numbers without fraction or exponent are
+// integers: int64, or *big.Int when they don't fit
+func processNumber() {
+	if source[start] == '0' && numberBase(peek()) != 0 {
+		processPrefixedNumber()
+		return
+	}
+
+	scanDigits()
+	if !validSeparators(string(source[start:current])) {
+		numberError("misplaced digit separator")
+		return
+	}
+
+	// numbers have no properties, so these can only be malformed fractions like 1._5 or 1.e5
+	if peek() == '.' && (peekNext() == '_' || peekNext() == 'e' || peekNext() == 'E') {
+		advance()
+		for isAlphanumeric(peek()) || ((peek() == '+' || peek() == '-') && isDigit(peekNext())) {
+			advance()
+		}
+		numberError("expected digits after '.'")
+		return
+	}
+
+	isFloat := false
+	if (peek() == '.') && isDigit(peekNext()) {
+		isFloat = true
+		advance()
+		fractionStart := current
+		scanDigits()
+		if !validSeparators(string(source[fractionStart:current])) {
+			numberError("misplaced digit separator")
+			return
+		}
+	}
+
+	if peek() == 'e' || peek() == 'E' {
+		isFloat = true
+		advance()
+		if peek() == '+' || peek() == '-' {
+			advance()
+		}
+		exponentStart := current
+		scanDigits()
+		exponent := string(source[exponentStart:current])
+		if exponent == "" {
+			numberError("missing exponent digits")
+			return
+		}
+		if !validSeparators(exponent) {
+			numberError("misplaced digit separator")
+			return
+		}
+	}
+
+	numberAsStr := strings.ReplaceAll(string(source[start:current]), "_", "")
+	if !isFloat {
+		addIntegerToken(numberAsStr, 10)
+		return
+	}
+	res, err := strconv.ParseFloat(numberAsStr, 64)
+	if err != nil {
+		numberError("out of range")
+		return
+	}
+	addTokenWithLiteral(def.NUMBER, res)
+}
+
+func processPrefixedNumber() {
+	base := numberBase(advance())
+	for isAlphanumeric(peek()) {
+		advance()
+	}
+
+	digits := string(source[start+2 : current])
+	if digits == "" {
+		numberError("missing digits")
+		return
+	}
+	if !validSeparators(digits) {
+		numberError("misplaced digit separator")
+		return
+	}
+	digits = strings.ReplaceAll(digits, "_", "")
+	for _, c := range digits {
+		if !isDigitOfBase(c, base) {
+			numberError(fmt.Sprintf("invalid digit '%c'", c))
+			return
+		}
+	}
+
+	addIntegerToken(digits, base)
+}
+
+func addIntegerToken(digits string, base int) {
+	res, err := strconv.ParseInt(digits, base, 64)
+	if err == nil {
+		addTokenWithLiteral(def.NUMBER, res)
+		return
+	}
+	bigRes, _ := new(big.Int).SetString(digits, base)
+	addTokenWithLiteral(def.NUMBER, bigRes)
+}
+
+func scanDigits() {
+	for isDigit(peek()) || peek() == '_' {
+		advance()
+	}
+}
+
+// validSeparators checks that every '_' is between two digits
+func validSeparators(digits string) bool {
+	return !strings.HasPrefix(digits, "_") &&
+		!strings.HasSuffix(digits, "_") &&
+		!strings.Contains(digits, "__")
+}
+
+func numberBase(prefix rune) int {
+	switch prefix {
+	case 'x', 'X':
+		return 16
+	case 'b', 'B':
+		return 2
+	case 'o', 'O':
+		return 8
+	}
+	return 0
+}
+
+func isDigitOfBase(c rune, base int) bool {
+	switch base {
+	case 2:
+		return c == '0' || c == '1'
+	case 8:
+		return c >= '0' && c <= '7'
+	}
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func numberError(reason string) {
+	def.LogError(line, fmt.Sprintf("Invalid number %s: %s", string(source[start:current]), reason))
+}
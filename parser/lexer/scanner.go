@@ -2,28 +2,39 @@ package lexer
 
 import (
 	"loxlang/parser/def"
-	"strconv"
 )
 
 var start, current, line int
+var file string
 var source []rune
 var tokens []def.Token
 
+// interpolations keeps, for each open "${", how many '{' are still open inside it
+var interpolations []int
+
 // ScanTokens is the main function of the lexer/scanner
 func ScanTokens(input string) []def.Token {
+	return ScanFile(input, "")
+}
+
+// ScanFile scans the content of a file, the tokens keep its name for error reporting
+func ScanFile(input string, filePath string) []def.Token {
+	file = filePath
 	tokens = []def.Token{}
-	source = make([]rune, len(input))
-	for i, r := range input {
-		source[i] = r
-	}
+	source = []rune(input)
 	start, current, line = 0, 0, 1
+	interpolations = []int{}
 
 	for !isAtEnd() {
 		start = current
 		scanToken()
 	}
 
-	tokens = append(tokens, def.Token{Type: def.EOF, Lexeme: "", Literal: nil, Line: line})
+	if len(interpolations) > 0 {
+		def.LogError(line, "Unterminated string interpolation")
+	}
+
+	tokens = append(tokens, def.Token{Type: def.EOF, Lexeme: "", Literal: nil, Line: line, File: file})
 	return tokens
 }
 
@@ -38,48 +49,127 @@ func scanToken() {
 		addToken(def.RIGHTPAREN)
 		break
 	case '{':
+		if len(interpolations) > 0 {
+			interpolations[len(interpolations)-1]++
+		}
 		addToken(def.LEFTBRACE)
 		break
 	case '}':
+		if len(interpolations) > 0 {
+			top := len(interpolations) - 1
+			if interpolations[top] == 0 {
+				// closes the "${", so the string goes on
+				interpolations = interpolations[:top]
+				processString()
+				break
+			}
+			interpolations[top]--
+		}
 		addToken(def.RIGHTBRACE)
 		break
+	case '[':
+		addToken(def.LEFTBRACKET)
+		break
+	case ']':
+		addToken(def.RIGHTBRACKET)
+		break
 	case ',':
 		addToken(def.COMMA)
 		break
+	case ':':
+		addToken(def.COLON)
+		break
+	case '?':
+		if match('?') {
+			addToken(def.QUESTIONQUESTION)
+		} else if match('.') {
+			addToken(def.QUESTIONDOT)
+		} else {
+			addToken(def.QUESTION)
+		}
+		break
 	case '.':
-		addToken(def.DOT)
+		if peek() == '.' && peekNext() == '.' {
+			advance()
+			advance()
+			addToken(def.ELLIPSIS)
+		} else {
+			addToken(composeLexeme('.', def.DOTDOT, def.DOT))
+		}
 		break
 	case '-':
-		addToken(def.MINUS)
+		if match('-') {
+			addToken(def.MINUSMINUS)
+		} else {
+			addToken(composeLexeme('=', def.MINUSEQUAL, def.MINUS))
+		}
 		break
 	case '+':
-		addToken(def.PLUS)
+		if match('+') {
+			addToken(def.PLUSPLUS)
+		} else {
+			addToken(composeLexeme('=', def.PLUSEQUAL, def.PLUS))
+		}
 		break
 	case ';':
 		addToken(def.SEMICOLON)
 		break
 	case '*':
-		addToken(def.STAR)
+		if match('*') {
+			addToken(def.STARSTAR)
+		} else {
+			addToken(composeLexeme('=', def.STAREQUAL, def.STAR))
+		}
+		break
+	case '%':
+		addToken(composeLexeme('=', def.PERCENTEQUAL, def.PERCENT))
+		break
+	case '&':
+		addToken(def.AMPERSAND)
+		break
+	case '|':
+		addToken(def.PIPE)
+		break
+	case '^':
+		addToken(def.CARET)
+		break
+	case '~':
+		// floor division is '~/', since '//' starts a comment
+		addToken(composeLexeme('/', def.TILDESLASH, def.TILDE))
 		break
 	case '!':
 		addToken(composeLexeme('=', def.BANGEQUAL, def.BANG))
 		break
 	case '=':
-		addToken(composeLexeme('=', def.EQUALEQUAL, def.EQUAL))
+		if match('>') {
+			addToken(def.ARROW)
+		} else {
+			addToken(composeLexeme('=', def.EQUALEQUAL, def.EQUAL))
+		}
 		break
 	case '<':
-		addToken(composeLexeme('=', def.LESSEQUAL, def.LESS))
+		if match('<') {
+			addToken(def.LESSLESS)
+		} else {
+			addToken(composeLexeme('=', def.LESSEQUAL, def.LESS))
+		}
 		break
 	case '>':
-		addToken(composeLexeme('=', def.GREATEREQUAL, def.GREATER))
+		if match('>') {
+			addToken(def.GREATERGREATER)
+		} else {
+			addToken(composeLexeme('=', def.GREATEREQUAL, def.GREATER))
+		}
 		break
 	case '/':
 		if match('/') {
 			for peek() != '\n' && !isAtEnd() {
 				advance()
 			}
+		} else if match('*') {
+			processBlockComment()
 		} else {
-			addToken(def.SLASH)
+			addToken(composeLexeme('=', def.SLASHEQUAL, def.SLASH))
 		}
 		break
 	case ' ':
@@ -92,10 +182,25 @@ func scanToken() {
 		break
 
 	case '"':
-		processString()
+		if peek() == '"' && peekNext() == '"' {
+			advance()
+			advance()
+			processHeredoc(false)
+		} else {
+			processString()
+		}
 		break
 	default:
-		if isDigit(char) {
+		if char == 'r' && peek() == '"' {
+			advance()
+			if peek() == '"' && peekNext() == '"' {
+				advance()
+				advance()
+				processHeredoc(true)
+			} else {
+				processRawString()
+			}
+		} else if isDigit(char) {
 			processNumber()
 		} else if isAlpha(char) {
 			processIdentifier()
@@ -106,49 +211,32 @@ func scanToken() {
 	}
 }
 
-func processString() {
-	for peek() != '"' && !isAtEnd() {
-		if peek() == '\n' {
+// processBlockComment skips /* ... */ comments, which can be nested
+func processBlockComment() {
+	openingLine := line
+	depth := 1
+	for depth > 0 && !isAtEnd() {
+		if peek() == '/' && peekNext() == '*' {
+			advance()
+			depth++
+		} else if peek() == '*' && peekNext() == '/' {
+			advance()
+			depth--
+		} else if peek() == '\n' {
 			line++
 		}
 		advance()
 	}
 
-	if isAtEnd() {
-		def.LogError(line, "Unterminated string")
-		return
+	if depth > 0 {
+		def.LogError(openingLine, "Unterminated block comment")
 	}
-
-	advance()
-	realString := string(source[start+1 : current-1])
-	addTokenWithLiteral(def.STRING, realString)
 }
 
 func isDigit(c rune) bool {
 	return c >= '0' && c <= '9'
 }
 
-func processNumber() {
-	for isDigit(peek()) {
-		advance()
-	}
-
-	if (peek() == '.') && isDigit(peekNext()) {
-		advance()
-
-		for isDigit(peek()) {
-			advance()
-		}
-	}
-
-	numberAsStr := string(source[start:current])
-	res, err := strconv.ParseFloat(numberAsStr, 64)
-	if err != nil {
-		def.LogError(line, "Invalid number "+numberAsStr)
-	}
-	addTokenWithLiteral(def.NUMBER, res)
-}
-
 func isAlpha(c rune) bool {
 	return (c >= 'a' && c <= 'z') ||
 		(c >= 'A' && c <= 'Z') ||
@@ -188,7 +276,7 @@ func addToken(tokenType def.TokenType) {
 
 func addTokenWithLiteral(tokenType def.TokenType, literal interface{}) {
 	content := source[start:current]
-	tokens = append(tokens, def.Token{tokenType, string(content), literal, line})
+	tokens = append(tokens, def.Token{Type: tokenType, Lexeme: string(content), Literal: literal, Line: line, File: file})
 }
 
 func composeLexeme(char rune, matches def.TokenType, replacement def.TokenType) def.TokenType {
@@ -220,8 +308,12 @@ func peek() rune {
 }
 
 func peekNext() rune {
-	if current+1 >= len(source) {
+	return lookAhead(1)
+}
+
+func lookAhead(offset int) rune {
+	if current+offset >= len(source) {
 		return '\x00'
 	}
-	return source[current+1]
+	return source[current+offset]
 }
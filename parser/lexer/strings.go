@@ -0,0 +1,205 @@
+package lexer
+
+import (
+	"fmt"
+	"loxlang/parser/def"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// processString handles "..." strings, which have escapes and "${...}" interpolation
+func processString() {
+	firstLine := line
+	for peek() != '"' && !isAtEnd() {
+		if peek() == '\\' {
+			// skips the escaped char, so \" and \$ don't end the string or open an interpolation
+			advance()
+		} else if peek() == '$' && peekNext() == '{' {
+			advance()
+			advance()
+			if emptyInterpolation() {
+				def.LogError(line, "Expect expression inside '${}'.")
+			}
+			addTokenWithLiteral(def.INTERPOLATION, unescape(source[start+1:current-2], firstLine))
+			interpolations = append(interpolations, 0)
+			return
+		}
+		if peek() == '\n' {
+			line++
+		}
+		if !isAtEnd() {
+			advance()
+		}
+	}
+
+	if isAtEnd() {
+		def.LogError(line, "Unterminated string")
+		return
+	}
+
+	advance()
+	addTokenWithLiteral(def.STRING, unescape(source[start+1:current-1], firstLine))
+}
+
+// emptyInterpolation checks if only blanks come between the "${" just read and its '}'
+func emptyInterpolation() bool {
+	for distance := 0; current+distance < len(source); distance++ {
+		switch source[current+distance] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '}':
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// processRawString handles r"..." strings, kept exactly as written
+func processRawString() {
+	for peek() != '"' && !isAtEnd() {
+		if peek() == '\n' {
+			line++
+		}
+		advance()
+	}
+
+	if isAtEnd() {
+		def.LogError(line, "Unterminated string")
+		return
+	}
+
+	advance()
+	addTokenWithLiteral(def.STRING, string(source[start+2:current-1]))
+}
+
+// processHeredoc handles """...""" multi-line strings, removing their common indentation
+func processHeredoc(raw bool) {
+	firstLine := line
+	contentStart := current
+	for !isAtEnd() && !(peek() == '"' && peekNext() == '"' && lookAhead(2) == '"') {
+		if peek() == '\\' && !raw {
+			advance()
+		}
+		if peek() == '\n' {
+			line++
+		}
+		if !isAtEnd() {
+			advance()
+		}
+	}
+
+	if isAtEnd() {
+		def.LogError(firstLine, "Unterminated multi-line string")
+		return
+	}
+
+	content, contentLine := dedent(source[contentStart:current], firstLine)
+	advance()
+	advance()
+	advance()
+	if raw {
+		addTokenWithLiteral(def.STRING, string(content))
+	} else {
+		addTokenWithLiteral(def.STRING, unescape(content, contentLine))
+	}
+}
+
+// dedent drops the line breaks after the opening and before the closing quotes,
+// and the indentation shared by all lines
+func dedent(content []rune, firstLine int) ([]rune, int) {
+	lines := strings.Split(string(content), "\n")
+	if len(lines) > 1 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+		firstLine++
+	}
+
+	indent := -1
+	for idx, l := range lines {
+		// the closing quotes line counts for the indentation even if it is blank
+		if strings.TrimSpace(l) == "" && idx != len(lines)-1 {
+			continue
+		}
+		width := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent == -1 || width < indent {
+			indent = width
+		}
+	}
+
+	// the line of the closing quotes only sets the indentation, its line break is dropped too
+	if len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for idx, l := range lines {
+		if len(l) >= indent {
+			lines[idx] = l[indent:]
+		} else {
+			lines[idx] = ""
+		}
+	}
+	return []rune(strings.Join(lines, "\n")), firstLine
+}
+
+// unescape replaces the escape sequences, errors are reported counting lines from firstLine
+func unescape(content []rune, firstLine int) string {
+	var builder strings.Builder
+	errLine := firstLine
+	for idx := 0; idx < len(content); idx++ {
+		char := content[idx]
+		if char == '\n' {
+			errLine++
+		}
+		if char != '\\' || idx+1 >= len(content) {
+			builder.WriteRune(char)
+			continue
+		}
+
+		idx++
+		switch content[idx] {
+		case 'n':
+			builder.WriteRune('\n')
+		case 't':
+			builder.WriteRune('\t')
+		case 'r':
+			builder.WriteRune('\r')
+		case '0':
+			builder.WriteRune('\x00')
+		case '"', '\\', '$':
+			builder.WriteRune(content[idx])
+		case 'u':
+			value, size, ok := unicodeEscape(content[idx+1:])
+			if !ok {
+				def.LogError(errLine, "Invalid unicode escape sequence")
+			}
+			builder.WriteRune(value)
+			idx += size
+		default:
+			if content[idx] == '\n' {
+				errLine++
+			}
+			def.LogError(errLine, fmt.Sprintf("Invalid escape sequence '\\%c'", content[idx]))
+		}
+	}
+	return builder.String()
+}
+
+// unicodeEscape reads the {XXXX} part of an \u{XXXX} escape, returning how many runes it used
+func unicodeEscape(content []rune) (rune, int, bool) {
+	if len(content) == 0 || content[0] != '{' {
+		return utf8.RuneError, 0, false
+	}
+	end := 1
+	for end < len(content) && content[end] != '}' && end <= 7 {
+		end++
+	}
+	if end >= len(content) || content[end] != '}' || end == 1 {
+		return utf8.RuneError, 0, false
+	}
+	code, err := strconv.ParseUint(string(content[1:end]), 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return utf8.RuneError, end + 1, false
+	}
+	return rune(code), end + 1, true
+}
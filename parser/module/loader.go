@@ -0,0 +1,162 @@
+package module
+
+import (
+	"fmt"
+	"io/ioutil"
+	"loxlang/parser"
+	"loxlang/parser/def"
+	"loxlang/parser/lexer"
+	"loxlang/parser/pass"
+	"loxlang/parser/runtime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Loader finds, runs and caches modules, each one runs only once
+type Loader struct {
+	SearchPath []string
+	modules    map[string]*runtime.LoxModule
+	loading    []string
+}
+
+// NewLoader creates a loader for the program in mainFile, looking for modules in searchPath
+// after the directory of the importing file
+func NewLoader(mainFile string, searchPath []string) *Loader {
+	loading := []string{}
+	if absPath, err := filepath.Abs(mainFile); err == nil && mainFile != "" {
+		loading = append(loading, absPath)
+	}
+	return &Loader{
+		SearchPath: searchPath,
+		modules:    map[string]*runtime.LoxModule{},
+		loading:    loading,
+	}
+}
+
+// SearchPath builds the module search path from a list of directories and the LOXPATH environment variable
+func SearchPath(dirs string) []string {
+	searchPath := []string{}
+	for _, dir := range filepath.SplitList(dirs) {
+		if dir != "" {
+			searchPath = append(searchPath, dir)
+		}
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("LOXPATH")) {
+		if dir != "" {
+			searchPath = append(searchPath, dir)
+		}
+	}
+	return searchPath
+}
+
+// Load runs the module in path, or returns it from cache if it already ran
+func (l *Loader) Load(importer *runtime.Interpreter, path string, from def.Token) (*runtime.LoxModule, *def.RuntimeError) {
+	filePath, ok := l.find(path, from.File)
+	if !ok {
+		return nil, &def.RuntimeError{
+			Token:   from,
+			Message: fmt.Sprintf("Module %s not found.", path),
+		}
+	}
+	absPath, _ := filepath.Abs(filePath)
+	if module, ok := l.modules[absPath]; ok {
+		return module, nil
+	}
+	for idx, loading := range l.loading {
+		if loading == absPath {
+			cycle := append(l.loading[idx:], absPath)
+			return nil, &def.RuntimeError{
+				Token:   from,
+				Message: fmt.Sprintf("Import cycle: %s.", strings.Join(cycle, " -> ")),
+			}
+		}
+	}
+
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, &def.RuntimeError{
+			Token:   from,
+			Message: fmt.Sprintf("Can't read module %s: %s.", path, err.Error()),
+		}
+	}
+
+	l.loading = append(l.loading, absPath)
+	defer func() { l.loading = l.loading[:len(l.loading)-1] }()
+	previousFile := def.CurrentFile
+	def.CurrentFile = filePath
+	defer func() { def.CurrentFile = previousFile }()
+
+	stmts, ok := compile(string(content), filePath)
+	if !ok {
+		return nil, &def.RuntimeError{
+			Token:   from,
+			Message: fmt.Sprintf("Can't load module %s.", path),
+		}
+	}
+
+	interpreter := runtime.NewModuleInterpreter(importer)
+	resolver := pass.NewResolver(*interpreter)
+	resolver.ResolveStmts(stmts)
+	if def.HadError {
+		def.HadError = false
+		return nil, &def.RuntimeError{
+			Token:   from,
+			Message: fmt.Sprintf("Can't load module %s.", path),
+		}
+	}
+
+	runErr := interpreter.Execute(stmts)
+	if runErr != nil {
+		return nil, runErr
+	}
+	module := &runtime.LoxModule{
+		Name:    path,
+		Globals: interpreter.Globals,
+	}
+	l.modules[absPath] = module
+	return module, nil
+}
+
+// compile scans and parses a module, compile errors are already reported when it fails
+func compile(content string, filePath string) ([]def.Stmt, bool) {
+	tokens := lexer.ScanFile(content, filePath)
+	if def.HadError {
+		def.HadError = false
+		return nil, false
+	}
+	stmts, _ := parser.Parse(tokens)
+	if def.HadError {
+		def.HadError = false
+		return nil, false
+	}
+	return stmts, true
+}
+
+// find looks for path next to the importing file first and then in the search path
+func (l *Loader) find(path string, importerFile string) (string, bool) {
+	if filepath.Ext(path) == "" {
+		path += ".lox"
+	}
+	if filepath.IsAbs(path) {
+		return path, isFile(path)
+	}
+
+	dirs := []string{"."}
+	if importerFile != "" {
+		dirs[0] = filepath.Dir(importerFile)
+	}
+	dirs = append(dirs, l.SearchPath...)
+	for _, dir := range dirs {
+		candidate := filepath.Join(dir, path)
+		if isFile(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -3,16 +3,25 @@ package parser
 import (
 	"fmt"
 	"loxlang/parser/def"
+	"math/big"
 )
 
 var tokens []def.Token
 var stmts []def.Stmt
 var current int
 
+// yields is set when a yield is found in the body of the function being parsed
+var yields bool
+
+// inGuard is set while parsing a match guard, where '=>' ends the guard instead of starting a lambda
+var inGuard bool
+
 // Parse is cool
 func Parse(input []def.Token) ([]def.Stmt, error) {
 	tokens = input
 	current = 0
+	yields = false
+	inGuard = false
 	stmts = []def.Stmt{}
 	for !isAtEnd() {
 		stmt, _ := declaration()
@@ -25,6 +34,22 @@ func Parse(input []def.Token) ([]def.Stmt, error) {
 }
 
 func declaration() (def.Stmt, error) {
+	if match(def.CLASS) {
+		classStmt, err := classDeclaration()
+		if err != nil {
+			def.HadError = true
+			synchronize()
+		}
+		return classStmt, nil
+	}
+	if match(def.ENUM) {
+		enumStmt, err := enumDeclaration()
+		if err != nil {
+			def.HadError = true
+			synchronize()
+		}
+		return enumStmt, nil
+	}
 	if check(def.FUN) && checkNext(def.IDENTIFIER) {
 		consume(def.FUN, "")
 		funStmt, funErr := function("function")
@@ -33,6 +58,32 @@ func declaration() (def.Stmt, error) {
 		}
 		return funStmt, nil
 	}
+	if match(def.IMPORT) {
+		importStmt, err := importDeclaration()
+		if err != nil {
+			def.HadError = true
+			synchronize()
+		}
+		return importStmt, nil
+	}
+	// 'from' is only a keyword when it starts an import, so it is still a valid name
+	if check(def.IDENTIFIER) && peek().Lexeme == "from" && checkNext(def.STRING) {
+		fromStmt, err := fromDeclaration()
+		if err != nil {
+			def.HadError = true
+			synchronize()
+		}
+		return fromStmt, nil
+	}
+	if check(def.VAR) && checkNext(def.LEFTPAREN) {
+		advance()
+		destructureStmt, err := destructureDeclaration()
+		if err != nil {
+			def.HadError = true
+			synchronize()
+		}
+		return destructureStmt, nil
+	}
 	if match(def.VAR) {
 		varStmt, err := varDeclaration()
 		if err != nil {
@@ -41,6 +92,14 @@ func declaration() (def.Stmt, error) {
 		}
 		return varStmt, nil
 	}
+	if match(def.CONST, def.LET) {
+		constStmt, err := constDeclaration()
+		if err != nil {
+			def.HadError = true
+			synchronize()
+		}
+		return constStmt, nil
+	}
 	stmt, err := statement()
 	if err != nil {
 		def.HadError = true
@@ -49,13 +108,91 @@ func declaration() (def.Stmt, error) {
 	return stmt, nil
 }
 
+func enumDeclaration() (def.Stmt, error) {
+	name, err := consume(def.IDENTIFIER, "Expect enum name.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.LEFTBRACE, "Expect '{' before enum members.")
+	if err != nil {
+		return nil, err
+	}
+	members := []def.Token{}
+	seen := map[string]bool{}
+	for !check(def.RIGHTBRACE) && !isAtEnd() {
+		member, memberErr := consume(def.IDENTIFIER, "Expect enum member name.")
+		if memberErr != nil {
+			return nil, memberErr
+		}
+		if seen[member.Lexeme] {
+			return nil, reportError(member, "Enum member declared twice.")
+		}
+		seen[member.Lexeme] = true
+		members = append(members, member)
+		if !match(def.COMMA) {
+			break
+		}
+	}
+	_, err = consume(def.RIGHTBRACE, "Expect '}' after enum members.")
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, reportError(name, "Enum must have at least one member.")
+	}
+	return &def.Enum{
+		Name:    name,
+		Members: members,
+	}, nil
+}
+
+func classDeclaration() (def.Stmt, error) {
+	name, err := consume(def.IDENTIFIER, "Expect class name.")
+	if err != nil {
+		return nil, err
+	}
+
+	var superclass *def.Variable
+	if match(def.LESS) {
+		superName, superErr := consume(def.IDENTIFIER, "Expect superclass name.")
+		if superErr != nil {
+			return nil, superErr
+		}
+		superclass = &def.Variable{Name: superName}
+	}
+
+	_, err = consume(def.LEFTBRACE, "Expect '{' before class body.")
+	if err != nil {
+		return nil, err
+	}
+
+	methods := []*def.Function{}
+	for !check(def.RIGHTBRACE) && !isAtEnd() {
+		method, methodErr := function("method")
+		if methodErr != nil {
+			return nil, methodErr
+		}
+		methods = append(methods, method.(*def.Function))
+	}
+
+	_, err = consume(def.RIGHTBRACE, "Expect '}' after class body.")
+	if err != nil {
+		return nil, err
+	}
+	return &def.Class{
+		Name:       name,
+		Superclass: superclass,
+		Methods:    methods,
+	}, nil
+}
+
 func function(kind string) (def.Stmt, error) {
 	name, err := consume(def.IDENTIFIER, fmt.Sprintf("Expected %s name.", kind))
 	if err != nil {
 		return nil, err
 	}
 
-	fnBody, fnErr := functionBody("function")
+	fnBody, fnErr := functionBody(kind)
 	if fnErr != nil {
 		return nil, fnErr
 	}
@@ -72,114 +209,655 @@ func functionBody(kind string) (def.Expr, error) {
 	if err != nil {
 		return nil, err
 	}
-	params := []def.Token{}
+	params, defaults, rest, err := parameters()
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.RIGHTPAREN, "Expect ')' after parameters.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.LEFTBRACE, fmt.Sprintf("Expect '{' before %s body.", kind))
+	if err != nil {
+		return nil, err
+	}
+	enclosingYields := yields
+	yields = false
+	body, bodyErr := block()
+	isGenerator := yields
+	yields = enclosingYields
+	if bodyErr != nil {
+		return nil, bodyErr
+	}
+	return &def.FunctionExpr{
+		Params:      params,
+		Defaults:    defaults,
+		Rest:        rest,
+		Body:        body,
+		IsGenerator: isGenerator,
+	}, nil
+}
+
+// parameters parses the params of functions and arrow lambdas, up to the closing paren.
+// Defaults has a nil entry for each param without default value
+func parameters() (params []def.Token, defaults []def.Expr, rest *def.Token, err error) {
+	params = []def.Token{}
+	defaults = []def.Expr{}
 	if !check(def.RIGHTPAREN) {
 		for {
 			if len(params) >= 127 {
 				def.CreateError(peek(), "Can't have more than 127 parameters")
 			}
+			if match(def.ELLIPSIS) {
+				restID, restErr := consume(def.IDENTIFIER, "Expect parameter name after '...'.")
+				if restErr != nil {
+					return nil, nil, nil, restErr
+				}
+				rest = &restID
+				if check(def.COMMA) {
+					return nil, nil, nil, reportError(peek(), "Variadic parameter must be the last one.")
+				}
+				break
+			}
 			paramID, paramErr := consume(def.IDENTIFIER, "Expect parameter name")
 			if paramErr != nil {
-				return nil, paramErr
+				return nil, nil, nil, paramErr
+			}
+			var defaultValue def.Expr
+			if match(def.EQUAL) {
+				defaultValue, paramErr = expression()
+				if paramErr != nil {
+					return nil, nil, nil, paramErr
+				}
+			} else if len(defaults) > 0 && defaults[len(defaults)-1] != nil {
+				return nil, nil, nil, reportError(paramID, "Parameter without default value can't follow one with a default.")
 			}
 			params = append(params, paramID)
+			defaults = append(defaults, defaultValue)
 			if !match(def.COMMA) {
 				break
 			}
 		}
 	}
-	_, err = consume(def.RIGHTPAREN, "Expect ')' after parameters.")
+	return params, defaults, rest, nil
+}
+
+func importDeclaration() (def.Stmt, error) {
+	keyword := previous()
+	path, err := consume(def.STRING, "Expect module path after 'import'.")
+	if err != nil {
+		return nil, err
+	}
+	as, err := consume(def.IDENTIFIER, "Expect 'as' after module path.")
+	if err != nil {
+		return nil, err
+	}
+	if as.Lexeme != "as" {
+		return nil, reportError(as, "Expect 'as' after module path.")
+	}
+	alias, err := consume(def.IDENTIFIER, "Expect module name after 'as'.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.SEMICOLON, "Expect ';' after import.")
+	if err != nil {
+		return nil, err
+	}
+	return &def.Import{
+		Keyword: keyword,
+		Path:    path,
+		Alias:   alias,
+	}, nil
+}
+
+func fromDeclaration() (def.Stmt, error) {
+	keyword := advance()
+	path := advance()
+	_, err := consume(def.IMPORT, "Expect 'import' after module path.")
+	if err != nil {
+		return nil, err
+	}
+	names := []def.Token{}
+	for {
+		name, nameErr := consume(def.IDENTIFIER, "Expect name to import.")
+		if nameErr != nil {
+			return nil, nameErr
+		}
+		names = append(names, name)
+		if !match(def.COMMA) {
+			break
+		}
+	}
+	_, err = consume(def.SEMICOLON, "Expect ';' after import.")
+	if err != nil {
+		return nil, err
+	}
+	return &def.Import{
+		Keyword: keyword,
+		Path:    path,
+		Names:   names,
+	}, nil
+}
+
+func varDeclaration() (def.Stmt, error) {
+	name, err := consume(def.IDENTIFIER, "Expect variable name")
+	if err != nil {
+		return nil, err
+	}
+	var initializer def.Expr
+	if match(def.EQUAL) {
+		initializer, err = expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = consume(def.SEMICOLON, "Expect ';' after variable declaration")
+	if err != nil {
+		return nil, err
+	}
+	return &def.Var{
+		Name:        name,
+		Initializer: initializer,
+	}, nil
+}
+
+func destructureDeclaration() (def.Stmt, error) {
+	keyword := previous()
+	advance()
+	names := []def.Token{}
+	var rest *def.Token
+	for {
+		if match(def.ELLIPSIS) {
+			restName, err := consume(def.IDENTIFIER, "Expect variable name after '...'.")
+			if err != nil {
+				return nil, err
+			}
+			rest = &restName
+			break
+		}
+		name, err := consume(def.IDENTIFIER, "Expect variable name")
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !match(def.COMMA) {
+			break
+		}
+	}
+	_, err := consume(def.RIGHTPAREN, "Expect ')' after destructured names.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.EQUAL, "Expect '=' after destructured names.")
+	if err != nil {
+		return nil, err
+	}
+	initializer, err := expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.SEMICOLON, "Expect ';' after variable declaration")
+	if err != nil {
+		return nil, err
+	}
+	return &def.Destructure{
+		Keyword:     keyword,
+		Names:       names,
+		Rest:        rest,
+		Initializer: initializer,
+	}, nil
+}
+
+func constDeclaration() (def.Stmt, error) {
+	keyword := previous()
+	name, err := consume(def.IDENTIFIER, "Expect constant name")
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.EQUAL, fmt.Sprintf("Expect '=' after %s name, it must be initialized", keyword.Lexeme))
+	if err != nil {
+		return nil, err
+	}
+	initializer, err := expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.SEMICOLON, "Expect ';' after constant declaration")
+	if err != nil {
+		return nil, err
+	}
+	return &def.Var{
+		Name:        name,
+		Initializer: initializer,
+		Const:       true,
+	}, nil
+}
+
+func statement() (def.Stmt, error) {
+	if match(def.FOR) {
+		return forStatement()
+	}
+
+	if match(def.IF) {
+		return ifStatement()
+	}
+
+	if match(def.PRINT) {
+		return printStatement()
+	}
+
+	if match(def.RETURN) {
+		return returnStatement()
+	}
+
+	if match(def.YIELD) {
+		return yieldStatement()
+	}
+
+	if match(def.WHILE) {
+		return whileStatement()
+	}
+
+	if match(def.DO) {
+		return doWhileStatement()
+	}
+
+	if check(def.IDENTIFIER) && checkNext(def.COLON) {
+		return labeledStatement()
+	}
+
+	if match(def.BREAK) {
+		return breakStatement()
+	}
+
+	if match(def.CONTINUE) {
+		return continueStatement()
+	}
+
+	if match(def.THROW) {
+		return throwStatement()
+	}
+
+	if match(def.TRY) {
+		return tryStatement()
+	}
+
+	if match(def.MATCH) {
+		return matchStatement()
+	}
+
+	if match(def.LEFTBRACE) {
+		stmts, err := block()
+		if err != nil {
+			return nil, err
+		}
+		return &def.Block{
+			Stmts: stmts,
+		}, nil
+	}
+	return expressionStatement()
+}
+
+func labeledStatement() (def.Stmt, error) {
+	label := advance()
+	advance()
+	if !check(def.WHILE) && !check(def.FOR) && !check(def.DO) {
+		return nil, reportError(peek(), "Only loops can be labeled.")
+	}
+	loop, err := statement()
+	if err != nil {
+		return nil, err
+	}
+	switch l := loop.(type) {
+	case *def.While:
+		l.Label = &label
+	case *def.ForIn:
+		l.Label = &label
+	case *def.Block:
+		// a for loop with initializer, the loop comes after it
+		l.Stmts[len(l.Stmts)-1].(*def.While).Label = &label
+	}
+	return loop, nil
+}
+
+func breakStatement() (def.Stmt, error) {
+	keyword := previous()
+	label := loopLabel()
+	_, err := consume(def.SEMICOLON, "Expect ';' after break keyword.")
+	if err != nil {
+		return nil, err
+	}
+	return &def.ControlFlow{
+		Keyword: keyword,
+		Type:    def.CONTROLFLOWBREAK,
+		Label:   label,
+	}, nil
+}
+
+func continueStatement() (def.Stmt, error) {
+	keyword := previous()
+	label := loopLabel()
+	_, err := consume(def.SEMICOLON, "Expect ';' after continue keyword.")
+	if err != nil {
+		return nil, err
+	}
+	return &def.ControlFlow{
+		Keyword: keyword,
+		Type:    def.CONTROLFLOWCONTINUE,
+		Label:   label,
+	}, nil
+}
+
+func loopLabel() *def.Token {
+	if match(def.IDENTIFIER) {
+		label := previous()
+		return &label
+	}
+	return nil
+}
+
+func throwStatement() (def.Stmt, error) {
+	keyword := previous()
+	value, err := expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.SEMICOLON, "Expect ';' after thrown value.")
+	if err != nil {
+		return nil, err
+	}
+	return &def.Throw{
+		Keyword: keyword,
+		Value:   value,
+	}, nil
+}
+
+func tryStatement() (def.Stmt, error) {
+	_, err := consume(def.LEFTBRACE, "Expect '{' after 'try'.")
+	if err != nil {
+		return nil, err
+	}
+	body, err := block()
+	if err != nil {
+		return nil, err
+	}
+	tryStmt := &def.Try{Body: body}
+
+	if match(def.CATCH) {
+		_, err = consume(def.LEFTPAREN, "Expect '(' after 'catch'.")
+		if err != nil {
+			return nil, err
+		}
+		tryStmt.CatchName, err = consume(def.IDENTIFIER, "Expect exception variable name.")
+		if err != nil {
+			return nil, err
+		}
+		_, err = consume(def.RIGHTPAREN, "Expect ')' after exception variable.")
+		if err != nil {
+			return nil, err
+		}
+		_, err = consume(def.LEFTBRACE, "Expect '{' before catch body.")
+		if err != nil {
+			return nil, err
+		}
+		tryStmt.CatchBody, err = block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if match(def.FINALLY) {
+		_, err = consume(def.LEFTBRACE, "Expect '{' after 'finally'.")
+		if err != nil {
+			return nil, err
+		}
+		tryStmt.FinallyBody, err = block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if tryStmt.CatchBody == nil && tryStmt.FinallyBody == nil {
+		return nil, reportError(peek(), "Expect 'catch' or 'finally' after try block.")
+	}
+	return tryStmt, nil
+}
+
+func matchStatement() (def.Stmt, error) {
+	keyword := previous()
+	_, err := consume(def.LEFTPAREN, "Expect '(' after 'match'.")
 	if err != nil {
 		return nil, err
 	}
-	_, err = consume(def.LEFTBRACE, fmt.Sprintf("Expect '{' before %s body.", kind))
+	subject, err := expression()
 	if err != nil {
 		return nil, err
 	}
-	body, bodyErr := block()
-	if bodyErr != nil {
-		return nil, bodyErr
+	_, err = consume(def.RIGHTPAREN, "Expect ')' after match value.")
+	if err != nil {
+		return nil, err
 	}
-	return &def.FunctionExpr{
-		Params: params,
-		Body:   body,
+	_, err = consume(def.LEFTBRACE, "Expect '{' before match cases.")
+	if err != nil {
+		return nil, err
+	}
+
+	cases := []*def.MatchCase{}
+	for !check(def.RIGHTBRACE) && !isAtEnd() {
+		matchCase, caseErr := matchCase()
+		if caseErr != nil {
+			return nil, caseErr
+		}
+		cases = append(cases, matchCase)
+	}
+	_, err = consume(def.RIGHTBRACE, "Expect '}' after match cases.")
+	if err != nil {
+		return nil, err
+	}
+	return &def.Match{
+		Keyword: keyword,
+		Subject: subject,
+		Cases:   cases,
 	}, nil
 }
 
-func varDeclaration() (def.Stmt, error) {
-	name, err := consume(def.IDENTIFIER, "Expect variable name")
+func matchCase() (*def.MatchCase, error) {
+	keyword, err := consume(def.CASE, "Expect 'case'.")
 	if err != nil {
 		return nil, err
 	}
-	var initializer def.Expr
-	if match(def.EQUAL) {
-		initializer, err = expression()
+	patterns := []*def.Pattern{}
+	for {
+		p, patternErr := pattern()
+		if patternErr != nil {
+			return nil, patternErr
+		}
+		patterns = append(patterns, p)
+		if !match(def.COMMA) {
+			break
+		}
+	}
+
+	var guard def.Expr
+	if match(def.IF) {
+		inGuard = true
+		guard, err = expression()
+		inGuard = false
 		if err != nil {
 			return nil, err
 		}
 	}
-	_, err = consume(def.SEMICOLON, "Expect ';' after variable declaration")
+	_, err = consume(def.ARROW, "Expect '=>' after case pattern.")
 	if err != nil {
 		return nil, err
 	}
-	return &def.Var{
-		Name:        name,
-		Initializer: initializer,
+	body, err := statement()
+	if err != nil {
+		return nil, err
+	}
+	return &def.MatchCase{
+		Keyword:  keyword,
+		Patterns: patterns,
+		Guard:    guard,
+		Body:     body,
 	}, nil
 }
 
-func statement() (def.Stmt, error) {
-	if match(def.FOR) {
-		return forStatement()
+func pattern() (*def.Pattern, error) {
+	if check(def.IDENTIFIER) && checkNext(def.DOT) {
+		return memberPattern()
 	}
 
-	if match(def.IF) {
-		return ifStatement()
+	if match(def.IDENTIFIER) {
+		name := previous()
+		if name.Lexeme == "_" {
+			return &def.Pattern{Kind: def.PATTERNWILDCARD, Token: name}, nil
+		}
+		return &def.Pattern{Kind: def.PATTERNBIND, Token: name}, nil
 	}
 
-	if match(def.PRINT) {
-		return printStatement()
+	if match(def.LEFTBRACKET) {
+		return listPattern()
 	}
 
-	if match(def.RETURN) {
-		return returnStatement()
+	if match(def.LEFTBRACE) {
+		return mapPattern()
 	}
 
-	if match(def.WHILE) {
-		return whileStatement()
+	token := peek()
+	value, err := patternLiteral()
+	if err != nil {
+		return nil, err
 	}
-
-	if match(def.BREAK) {
-		return breakStatement()
+	if match(def.DOTDOT) {
+		high, highErr := patternLiteral()
+		if highErr != nil {
+			return nil, highErr
+		}
+		return &def.Pattern{Kind: def.PATTERNRANGE, Token: token, Value: value, High: high}, nil
 	}
+	return &def.Pattern{Kind: def.PATTERNLITERAL, Token: token, Value: value}, nil
+}
 
-	if match(def.LEFTBRACE) {
-		stmts, err := block()
+// memberPattern parses qualified names like Color.Red, matching the value they hold
+func memberPattern() (*def.Pattern, error) {
+	name := advance()
+	var member def.Expr = &def.Variable{Name: name}
+	for match(def.DOT) {
+		property, err := consume(def.IDENTIFIER, "Expect property name after '.'.")
 		if err != nil {
 			return nil, err
 		}
-		return &def.Block{
-			Stmts: stmts,
-		}, nil
+		member = &def.Get{Object: member, Name: property}
 	}
-	return expressionStatement()
+	return &def.Pattern{Kind: def.PATTERNMEMBER, Token: name, Member: member.(*def.Get)}, nil
 }
 
-func breakStatement() (def.Stmt, error) {
-	_, err := consume(def.SEMICOLON, "Expect ';' after break keyword.")
+func listPattern() (*def.Pattern, error) {
+	listPattern := &def.Pattern{Kind: def.PATTERNLIST, Token: previous(), Elements: []*def.Pattern{}}
+	if !check(def.RIGHTBRACKET) {
+		for {
+			if match(def.ELLIPSIS) {
+				rest, err := consume(def.IDENTIFIER, "Expect name after '...'.")
+				if err != nil {
+					return nil, err
+				}
+				listPattern.Rest = &rest
+				break
+			}
+			element, err := pattern()
+			if err != nil {
+				return nil, err
+			}
+			listPattern.Elements = append(listPattern.Elements, element)
+			if !match(def.COMMA) {
+				break
+			}
+		}
+	}
+	_, err := consume(def.RIGHTBRACKET, "Expect ']' after list pattern.")
 	if err != nil {
 		return nil, err
 	}
-	return &def.ControlFlow{
-		Type: def.CONTROLFLOWBREAK,
-	}, nil
+	return listPattern, nil
+}
+
+func mapPattern() (*def.Pattern, error) {
+	mapPattern := &def.Pattern{Kind: def.PATTERNMAP, Token: previous(), Keys: []interface{}{}, Elements: []*def.Pattern{}}
+	if !check(def.RIGHTBRACE) {
+		for {
+			key, err := patternLiteral()
+			if err != nil {
+				return nil, err
+			}
+			_, err = consume(def.COLON, "Expect ':' after map pattern key.")
+			if err != nil {
+				return nil, err
+			}
+			value, err := pattern()
+			if err != nil {
+				return nil, err
+			}
+			mapPattern.Keys = append(mapPattern.Keys, key)
+			mapPattern.Elements = append(mapPattern.Elements, value)
+			if !match(def.COMMA) {
+				break
+			}
+		}
+	}
+	_, err := consume(def.RIGHTBRACE, "Expect '}' after map pattern.")
+	if err != nil {
+		return nil, err
+	}
+	return mapPattern, nil
+}
+
+func patternLiteral() (interface{}, error) {
+	if match(def.NUMBER, def.STRING) {
+		return previous().Literal, nil
+	}
+	if match(def.MINUS) {
+		number, err := consume(def.NUMBER, "Expect number after '-' in pattern.")
+		if err != nil {
+			return nil, err
+		}
+		return negateLiteral(number.Literal), nil
+	}
+	if match(def.TRUE) {
+		return true, nil
+	}
+	if match(def.FALSE) {
+		return false, nil
+	}
+	if match(def.NIL) {
+		return nil, nil
+	}
+	return nil, reportError(peek(), "Expect pattern.")
+}
+
+func negateLiteral(number interface{}) interface{} {
+	switch value := number.(type) {
+	case int64:
+		return -value
+	case *big.Int:
+		negated := new(big.Int).Neg(value)
+		if negated.IsInt64() {
+			return negated.Int64()
+		}
+		return negated
+	}
+	return -number.(float64)
 }
 
 func forStatement() (def.Stmt, error) {
+	keyword := previous()
 	_, err := consume(def.LEFTPAREN, "Expect '(' after 'for'.")
 	if err != nil {
 		return nil, err
 	}
+	if check(def.VAR) && checkNext(def.IDENTIFIER) && checkAhead(2, def.IN) {
+		return forInStatement(keyword)
+	}
 
 	var initializer def.Stmt
 	if match(def.SEMICOLON) {
@@ -225,22 +903,14 @@ func forStatement() (def.Stmt, error) {
 		return nil, err
 	}
 
-	if increment != nil {
-		stmts := []def.Stmt{}
-		body = &def.Block{
-			Stmts: append(stmts, body,
-				&def.ExprStmt{
-					Expr: increment,
-				}),
-		}
-	}
-
 	if condition == nil {
 		condition = &def.Literal{Value: true}
 	}
+	// the increment is kept apart from the body so 'continue' doesn't skip it
 	body = &def.While{
 		Condition: condition,
 		Body:      body,
+		Increment: increment,
 	}
 
 	if initializer != nil {
@@ -253,6 +923,30 @@ func forStatement() (def.Stmt, error) {
 	return body, nil
 }
 
+func forInStatement(keyword def.Token) (def.Stmt, error) {
+	advance()
+	name := advance()
+	advance()
+	iterable, err := expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.RIGHTPAREN, "Expect ')' after for-in iterable.")
+	if err != nil {
+		return nil, err
+	}
+	body, err := statement()
+	if err != nil {
+		return nil, err
+	}
+	return &def.ForIn{
+		Keyword:  keyword,
+		Name:     name,
+		Iterable: iterable,
+		Body:     body,
+	}, nil
+}
+
 func returnStatement() (def.Stmt, error) {
 	var value def.Expr
 	var err error
@@ -262,6 +956,12 @@ func returnStatement() (def.Stmt, error) {
 		if err != nil {
 			return nil, err
 		}
+		if check(def.COMMA) {
+			value, err = tuple(keyword, value)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 	_, err = consume(def.SEMICOLON, "Expect ';' after return value")
 	if err != nil {
@@ -273,6 +973,43 @@ func returnStatement() (def.Stmt, error) {
 	}, nil
 }
 
+func yieldStatement() (def.Stmt, error) {
+	var value def.Expr
+	var err error
+	keyword := previous()
+	yields = true
+	if !check(def.SEMICOLON) {
+		value, err = expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = consume(def.SEMICOLON, "Expect ';' after yield value")
+	if err != nil {
+		return nil, err
+	}
+	return &def.Yield{
+		Keyword: keyword,
+		Value:   value,
+	}, nil
+}
+
+// tuple parses the rest of return a, b, c;
+func tuple(keyword def.Token, first def.Expr) (def.Expr, error) {
+	elements := []def.Expr{first}
+	for match(def.COMMA) {
+		element, err := expression()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+	return &def.Tuple{
+		Token:    keyword,
+		Elements: elements,
+	}, nil
+}
+
 func whileStatement() (def.Stmt, error) {
 	_, err := consume(def.LEFTPAREN, "Expect '(' after 'while'.")
 	if err != nil {
@@ -296,6 +1033,38 @@ func whileStatement() (def.Stmt, error) {
 	}, nil
 }
 
+func doWhileStatement() (def.Stmt, error) {
+	body, err := statement()
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.WHILE, "Expect 'while' after 'do' body.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.LEFTPAREN, "Expect '(' after 'while'.")
+	if err != nil {
+		return nil, err
+	}
+	condition, err := expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.RIGHTPAREN, "Expect ')' after 'while' condition.")
+	if err != nil {
+		return nil, err
+	}
+	_, err = consume(def.SEMICOLON, "Expect ';' after do-while.")
+	if err != nil {
+		return nil, err
+	}
+	return &def.While{
+		Condition: condition,
+		Body:      body,
+		Do:        true,
+	}, nil
+}
+
 func ifStatement() (def.Stmt, error) {
 	_, err := consume(def.LEFTPAREN, "Expect '(' after 'if'.")
 	if err != nil {
@@ -346,6 +1115,7 @@ func block() ([]def.Stmt, error) {
 }
 
 func printStatement() (def.Stmt, error) {
+	keyword := previous()
 	expr, err := expression()
 	if err != nil {
 		return nil, err
@@ -355,48 +1125,153 @@ func printStatement() (def.Stmt, error) {
 		return nil, consErr
 	}
 	return &def.Print{
+		Keyword: keyword,
+		Expr:    expr,
+	}, nil
+}
+
+func expressionStatement() (def.Stmt, error) {
+	expr, err := expression()
+	if err != nil {
+		return nil, err
+	}
+	_, consErr := consume(def.SEMICOLON, "Expect ';' after value")
+	if consErr != nil {
+		return nil, consErr
+	}
+	return &def.ExprStmt{
 		Expr: expr,
 	}, nil
 }
 
-func expressionStatement() (def.Stmt, error) {
-	expr, err := expression()
+func expression() (def.Expr, error) {
+	return assignment()
+}
+
+func assignment() (def.Expr, error) {
+	expr, err := conditional()
+	if err != nil {
+		return nil, err
+	}
+	if match(def.EQUAL) {
+		equals := previous()
+		value, assignErr := assignment()
+
+		if assignErr != nil {
+			return nil, assignErr
+		}
+		if variable, res := expr.(*def.Variable); res {
+			name := variable.Name
+			return &def.Assign{
+				Name:  name,
+				Value: value,
+			}, nil
+		}
+		if get, res := expr.(*def.Get); res && !get.Optional {
+			return &def.Set{
+				Object: get.Object,
+				Name:   get.Name,
+				Value:  value,
+			}, nil
+		}
+		if index, res := expr.(*def.Index); res {
+			return &def.IndexSet{
+				Object:  index.Object,
+				Bracket: index.Bracket,
+				Index:   index.Index,
+				Value:   value,
+			}, nil
+		}
+		reportError(equals, "Invalid assign target")
+	}
+	if match(def.PLUSEQUAL, def.MINUSEQUAL, def.STAREQUAL, def.SLASHEQUAL, def.PERCENTEQUAL) {
+		operator := previous()
+		value, assignErr := assignment()
+		if assignErr != nil {
+			return nil, assignErr
+		}
+		if !isAssignable(expr) {
+			return nil, reportError(operator, "Invalid assign target")
+		}
+		return &def.CompoundAssign{
+			Target:   expr,
+			Operator: compoundOperator(operator),
+			Value:    value,
+		}, nil
+	}
+	return expr, nil
+}
+
+func isAssignable(expr def.Expr) bool {
+	switch target := expr.(type) {
+	case *def.Variable, *def.Index:
+		return true
+	case *def.Get:
+		return !target.Optional
+	}
+	return false
+}
+
+// compoundOperator turns += into + and so on, keeping lexeme and line for errors
+func compoundOperator(operator def.Token) def.Token {
+	types := map[def.TokenType]def.TokenType{
+		def.PLUSEQUAL:    def.PLUS,
+		def.MINUSEQUAL:   def.MINUS,
+		def.STAREQUAL:    def.STAR,
+		def.SLASHEQUAL:   def.SLASH,
+		def.PERCENTEQUAL: def.PERCENT,
+		def.PLUSPLUS:     def.PLUS,
+		def.MINUSMINUS:   def.MINUS,
+	}
+	operator.Type = types[operator.Type]
+	return operator
+}
+
+func conditional() (def.Expr, error) {
+	expr, err := coalesce()
 	if err != nil {
 		return nil, err
 	}
-	_, consErr := consume(def.SEMICOLON, "Expect ';' after value")
-	if consErr != nil {
-		return nil, consErr
+	if match(def.QUESTION) {
+		question := previous()
+		thenBranch, thenErr := expression()
+		if thenErr != nil {
+			return nil, thenErr
+		}
+		_, err = consume(def.COLON, "Expect ':' after then branch of conditional expression.")
+		if err != nil {
+			return nil, err
+		}
+		elseBranch, elseErr := conditional()
+		if elseErr != nil {
+			return nil, elseErr
+		}
+		expr = &def.Conditional{
+			Condition: expr,
+			Question:  question,
+			Then:      thenBranch,
+			Else:      elseBranch,
+		}
 	}
-	return &def.ExprStmt{
-		Expr: expr,
-	}, nil
-}
-
-func expression() (def.Expr, error) {
-	return assignment()
+	return expr, nil
 }
 
-func assignment() (def.Expr, error) {
+func coalesce() (def.Expr, error) {
 	expr, err := or()
 	if err != nil {
 		return nil, err
 	}
-	if match(def.EQUAL) {
-		equals := previous()
-		value, assignErr := assignment()
-
-		if assignErr != nil {
-			return nil, assignErr
+	for match(def.QUESTIONQUESTION) {
+		operator := previous()
+		right, rErr := or()
+		if rErr != nil {
+			return nil, rErr
 		}
-		if variable, res := expr.(*def.Variable); res {
-			name := variable.Name
-			return &def.Assign{
-				Name:  name,
-				Value: value,
-			}, nil
+		expr = &def.Logical{
+			Operator: operator,
+			Left:     expr,
+			Right:    right,
 		}
-		reportError(equals, "Invalid assign target")
 	}
 	return expr, nil
 }
@@ -462,13 +1337,13 @@ func equality() (def.Expr, error) {
 }
 
 func comparison() (def.Expr, error) {
-	expr, err := term()
+	expr, err := bitOr()
 	if err != nil {
 		return &def.EmptyExpr{}, err
 	}
 	for match(def.GREATER, def.GREATEREQUAL, def.LESS, def.LESSEQUAL) {
 		operator := previous()
-		right, errInside := term()
+		right, errInside := bitOr()
 		if errInside != nil {
 			return &def.EmptyExpr{}, err
 		}
@@ -481,6 +1356,86 @@ func comparison() (def.Expr, error) {
 	return expr, nil
 }
 
+func bitOr() (def.Expr, error) {
+	expr, err := bitXor()
+	if err != nil {
+		return &def.EmptyExpr{}, err
+	}
+	for match(def.PIPE) {
+		operator := previous()
+		right, errInside := bitXor()
+		if errInside != nil {
+			return &def.EmptyExpr{}, errInside
+		}
+		expr = &def.Binary{
+			Left:  expr,
+			Token: operator,
+			Right: right,
+		}
+	}
+	return expr, nil
+}
+
+func bitXor() (def.Expr, error) {
+	expr, err := bitAnd()
+	if err != nil {
+		return &def.EmptyExpr{}, err
+	}
+	for match(def.CARET) {
+		operator := previous()
+		right, errInside := bitAnd()
+		if errInside != nil {
+			return &def.EmptyExpr{}, errInside
+		}
+		expr = &def.Binary{
+			Left:  expr,
+			Token: operator,
+			Right: right,
+		}
+	}
+	return expr, nil
+}
+
+func bitAnd() (def.Expr, error) {
+	expr, err := shift()
+	if err != nil {
+		return &def.EmptyExpr{}, err
+	}
+	for match(def.AMPERSAND) {
+		operator := previous()
+		right, errInside := shift()
+		if errInside != nil {
+			return &def.EmptyExpr{}, errInside
+		}
+		expr = &def.Binary{
+			Left:  expr,
+			Token: operator,
+			Right: right,
+		}
+	}
+	return expr, nil
+}
+
+func shift() (def.Expr, error) {
+	expr, err := term()
+	if err != nil {
+		return &def.EmptyExpr{}, err
+	}
+	for match(def.LESSLESS, def.GREATERGREATER) {
+		operator := previous()
+		right, errInside := term()
+		if errInside != nil {
+			return &def.EmptyExpr{}, errInside
+		}
+		expr = &def.Binary{
+			Left:  expr,
+			Token: operator,
+			Right: right,
+		}
+	}
+	return expr, nil
+}
+
 func term() (def.Expr, error) {
 	expr, err := factor()
 	if err != nil {
@@ -506,7 +1461,7 @@ func factor() (def.Expr, error) {
 	if err != nil {
 		return &def.EmptyExpr{}, err
 	}
-	for match(def.SLASH, def.STAR) {
+	for match(def.SLASH, def.STAR, def.PERCENT, def.TILDESLASH) {
 		operator := previous()
 		right, errInside := unary()
 		if errInside != nil {
@@ -522,7 +1477,7 @@ func factor() (def.Expr, error) {
 }
 
 func unary() (def.Expr, error) {
-	if match(def.BANG, def.MINUS) {
+	if match(def.BANG, def.MINUS, def.TILDE) {
 		operator := previous()
 		right, err := unary()
 		if err != nil {
@@ -533,7 +1488,62 @@ func unary() (def.Expr, error) {
 			Right: right,
 		}, nil
 	}
-	return call()
+	if match(def.PLUSPLUS, def.MINUSMINUS) {
+		operator := previous()
+		target, err := unary()
+		if err != nil {
+			return nil, err
+		}
+		if !isAssignable(target) {
+			return nil, reportError(operator, "Invalid increment target")
+		}
+		return &def.Increment{
+			Target:   target,
+			Operator: compoundOperator(operator),
+			Prefix:   true,
+		}, nil
+	}
+	return power()
+}
+
+// power is right-associative and binds tighter than unary on its left: -2 ** 2 is -(2 ** 2)
+func power() (def.Expr, error) {
+	expr, err := postfix()
+	if err != nil {
+		return nil, err
+	}
+	if match(def.STARSTAR) {
+		operator := previous()
+		right, errInside := unary()
+		if errInside != nil {
+			return nil, errInside
+		}
+		expr = &def.Binary{
+			Left:  expr,
+			Token: operator,
+			Right: right,
+		}
+	}
+	return expr, nil
+}
+
+func postfix() (def.Expr, error) {
+	expr, err := call()
+	if err != nil {
+		return nil, err
+	}
+	if match(def.PLUSPLUS, def.MINUSMINUS) {
+		operator := previous()
+		if !isAssignable(expr) {
+			return nil, reportError(operator, "Invalid increment target")
+		}
+		return &def.Increment{
+			Target:   expr,
+			Operator: compoundOperator(operator),
+			Prefix:   false,
+		}, nil
+	}
+	return expr, nil
 }
 
 func call() (def.Expr, error) {
@@ -541,31 +1551,85 @@ func call() (def.Expr, error) {
 	if err != nil {
 		return nil, err
 	}
+	optionalChain := false
 	for {
 		if match(def.LEFTPAREN) {
 			expr, err = finishCall(expr)
 			if err != nil {
 				return nil, err
 			}
+		} else if match(def.DOT, def.QUESTIONDOT) {
+			optional := previous().Type == def.QUESTIONDOT
+			optionalChain = optionalChain || optional
+			name, nameErr := consume(def.IDENTIFIER, "Expect property name after '.'.")
+			if nameErr != nil {
+				return nil, nameErr
+			}
+			expr = &def.Get{
+				Object:   expr,
+				Name:     name,
+				Optional: optional,
+			}
+		} else if match(def.LEFTBRACKET) {
+			bracket := previous()
+			index, indexErr := expression()
+			if indexErr != nil {
+				return nil, indexErr
+			}
+			_, indexErr = consume(def.RIGHTBRACKET, "Expect ']' after index.")
+			if indexErr != nil {
+				return nil, indexErr
+			}
+			expr = &def.Index{
+				Object:  expr,
+				Bracket: bracket,
+				Index:   index,
+			}
 		} else {
 			break
 		}
 	}
+	if optionalChain {
+		return &def.OptionalChain{Expr: expr}, nil
+	}
 	return expr, nil
 }
 
+// leaveGuard clears inGuard until the returned func restores it. Inside parens and brackets
+// a '=>' can only start a lambda, even in a match guard
+func leaveGuard() func() {
+	enclosingGuard := inGuard
+	inGuard = false
+	return func() { inGuard = enclosingGuard }
+}
+
 func finishCall(callee def.Expr) (def.Expr, error) {
+	defer leaveGuard()()
 	args := []def.Expr{}
+	namedArgs := []*def.NamedArgument{}
 	if !check(def.RIGHTPAREN) {
 		for {
-			if len(args) >= 127 {
+			if len(args)+len(namedArgs) >= 127 {
 				return nil, reportError(peek(), "Can't have more than 127 arguments in a function")
 			}
-			expr, err := expression()
-			if err != nil {
-				return nil, err
+			if check(def.IDENTIFIER) && checkNext(def.COLON) {
+				name := advance()
+				advance()
+				expr, err := expression()
+				if err != nil {
+					return nil, err
+				}
+				namedArgs = append(namedArgs, &def.NamedArgument{Name: name, Value: expr})
+			} else {
+				if len(namedArgs) > 0 {
+					return nil, reportError(peek(), "Positional arguments can't follow named arguments.")
+				}
+				expr, err := expression()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, expr)
 			}
-			args = append(args, expr)
 			if !match(def.COMMA) {
 				break
 			}
@@ -576,14 +1640,19 @@ func finishCall(callee def.Expr) (def.Expr, error) {
 		return nil, parenErr
 	}
 	return &def.Call{
-		Callee:    callee,
-		Paren:     paren,
-		Arguments: args,
+		Callee:         callee,
+		Paren:          paren,
+		Arguments:      args,
+		NamedArguments: namedArgs,
 	}, nil
 
 }
 
 func primary() (def.Expr, error) {
+	if !inGuard && arrowAhead() {
+		return arrowFunction()
+	}
+
 	if match(def.FUN) {
 		expr, fnErr := functionBody("function")
 		if fnErr != nil {
@@ -608,19 +1677,225 @@ func primary() (def.Expr, error) {
 		return &def.Literal{Value: previous().Literal}, nil
 	}
 
+	if match(def.INTERPOLATION) {
+		return interpolation()
+	}
+
+	if match(def.SUPER) {
+		keyword := previous()
+		_, err := consume(def.DOT, "Expect '.' after 'super'.")
+		if err != nil {
+			return nil, err
+		}
+		method, err := consume(def.IDENTIFIER, "Expect superclass method name.")
+		if err != nil {
+			return nil, err
+		}
+		return &def.Super{Keyword: keyword, Method: method}, nil
+	}
+
+	if match(def.THIS) {
+		return &def.This{Keyword: previous()}, nil
+	}
+
 	if match(def.IDENTIFIER) {
 		return &def.Variable{Name: previous()}, nil
 	}
 
+	if match(def.LEFTBRACKET) {
+		return listLiteral()
+	}
+
+	// statement() already took any '{' that starts a block, so here it can only be a map
+	if match(def.LEFTBRACE) {
+		return mapLiteral()
+	}
+
 	if match(def.LEFTPAREN) {
+		restoreGuard := leaveGuard()
 		expr, _ := expression()
 		consume(def.RIGHTPAREN, "EXPECT '(' after expression")
+		restoreGuard()
 		return &def.Grouping{Expression: expr}, nil
 	}
 
 	return nil, reportError(peek(), "Expects expression")
 }
 
+// arrowAhead looks for 'v =>' or '(a, b) =>' without consuming. The first tokens after '(' decide,
+// a grouping never starts with '...' nor has a ',' after a name. Only '(a = ...' can also be an
+// assignment in parens, then the arrow is looked for after the matching paren
+func arrowAhead() bool {
+	if check(def.IDENTIFIER) {
+		return checkNext(def.ARROW)
+	}
+	if !check(def.LEFTPAREN) {
+		return false
+	}
+	switch {
+	case checkAhead(1, def.RIGHTPAREN):
+		return checkAhead(2, def.ARROW)
+	case checkAhead(1, def.ELLIPSIS):
+		return true
+	case !checkAhead(1, def.IDENTIFIER):
+		return false
+	case checkAhead(2, def.COMMA):
+		return true
+	case checkAhead(2, def.RIGHTPAREN):
+		return checkAhead(3, def.ARROW)
+	case checkAhead(2, def.EQUAL):
+		return arrowAfterParen()
+	}
+	return false
+}
+
+// arrowAfterParen checks if the paren at the current token is followed by '=>' once closed
+func arrowAfterParen() bool {
+	depth := 0
+	for distance := 0; current+distance < len(tokens); distance++ {
+		switch tokens[current+distance].Type {
+		case def.LEFTPAREN:
+			depth++
+		case def.RIGHTPAREN:
+			depth--
+			if depth == 0 {
+				return checkAhead(distance+1, def.ARROW)
+			}
+		case def.EOF:
+			return false
+		}
+	}
+	return false
+}
+
+// arrowFunction parses (a, b = 1, ...rest) => expression as fun (a, b = 1, ...rest) { return expression; }
+func arrowFunction() (def.Expr, error) {
+	params := []def.Token{}
+	defaults := []def.Expr{}
+	var rest *def.Token
+	if match(def.IDENTIFIER) {
+		params = append(params, previous())
+		defaults = append(defaults, nil)
+	} else {
+		advance()
+		var err error
+		params, defaults, rest, err = parameters()
+		if err != nil {
+			return nil, err
+		}
+		_, err = consume(def.RIGHTPAREN, "Expect ')' after parameters.")
+		if err != nil {
+			return nil, err
+		}
+	}
+	arrow, err := consume(def.ARROW, "Expect '=>' after parameters.")
+	if err != nil {
+		return nil, err
+	}
+	body, err := expression()
+	if err != nil {
+		return nil, err
+	}
+	return &def.FunctionExpr{
+		Params:   params,
+		Defaults: defaults,
+		Rest:     rest,
+		Body: []def.Stmt{&def.Return{
+			Keyword: arrow,
+			Value:   body,
+		}},
+	}, nil
+}
+
+func interpolation() (def.Expr, error) {
+	token := previous()
+	parts := []def.Expr{&def.Literal{Value: token.Literal}}
+	for {
+		expr, err := expression()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, expr)
+		if match(def.INTERPOLATION) {
+			parts = append(parts, &def.Literal{Value: previous().Literal})
+			continue
+		}
+		_, err = consume(def.STRING, "Expect '}' after interpolated expression.")
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, &def.Literal{Value: previous().Literal})
+		break
+	}
+	return &def.Interpolation{
+		Token: token,
+		Parts: parts,
+	}, nil
+}
+
+func listLiteral() (def.Expr, error) {
+	defer leaveGuard()()
+	bracket := previous()
+	elements := []def.Expr{}
+	if !check(def.RIGHTBRACKET) {
+		for {
+			element, err := expression()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+			if !match(def.COMMA) {
+				break
+			}
+		}
+	}
+	_, err := consume(def.RIGHTBRACKET, "Expect ']' after list elements.")
+	if err != nil {
+		return nil, err
+	}
+	return &def.ListLiteral{
+		Bracket:  bracket,
+		Elements: elements,
+	}, nil
+}
+
+func mapLiteral() (def.Expr, error) {
+	defer leaveGuard()()
+	brace := previous()
+	keys := []def.Expr{}
+	values := []def.Expr{}
+	if !check(def.RIGHTBRACE) {
+		for {
+			key, err := expression()
+			if err != nil {
+				return nil, err
+			}
+			_, err = consume(def.COLON, "Expect ':' after map key.")
+			if err != nil {
+				return nil, err
+			}
+			value, err := expression()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			values = append(values, value)
+			if !match(def.COMMA) {
+				break
+			}
+		}
+	}
+	_, err := consume(def.RIGHTBRACE, "Expect '}' after map entries.")
+	if err != nil {
+		return nil, err
+	}
+	return &def.MapLiteral{
+		Brace:  brace,
+		Keys:   keys,
+		Values: values,
+	}, nil
+}
+
 func checkNext(tokenType def.TokenType) bool {
 	if isAtEnd() {
 		return false
@@ -631,6 +1906,16 @@ func checkNext(tokenType def.TokenType) bool {
 	return tokens[current+1].Type == tokenType
 }
 
+// checkAhead looks at the token distance places after the current one
+func checkAhead(distance int, tokenType def.TokenType) bool {
+	for offset := 0; offset < distance; offset++ {
+		if current+offset >= len(tokens) || tokens[current+offset].Type == def.EOF {
+			return false
+		}
+	}
+	return tokens[current+distance].Type == tokenType
+}
+
 func synchronize() {
 	advance()
 
@@ -639,14 +1924,8 @@ func synchronize() {
 			return
 		}
 		switch peek().Type {
-		case def.CLASS:
-		case def.FUN:
-		case def.VAR:
-		case def.FOR:
-		case def.IF:
-		case def.WHILE:
-		case def.PRINT:
-		case def.RETURN:
+		case def.CLASS, def.ENUM, def.FUN, def.VAR, def.CONST, def.LET, def.IMPORT,
+			def.FOR, def.IF, def.WHILE, def.DO, def.PRINT, def.RETURN, def.TRY, def.THROW, def.MATCH:
 			return
 		}
 		advance()
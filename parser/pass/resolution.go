@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"loxlang/parser/def"
 	"loxlang/parser/runtime"
+	"strings"
 )
 
 type fnScope int
@@ -12,21 +13,55 @@ type fnScope int
 const (
 	ScopeNone fnScope = iota
 	ScopeFunction
+	ScopeMethod
+	ScopeInitializer
+	ScopeGenerator
+)
+
+type classScope int
+
+// Identifies if it is in class scope or not
+const (
+	ClassNone classScope = iota
+	ClassClass
+	ClassSubclass
 )
 
 // Resolver Walks the parse tree doing static analyses - variable resolution
 type Resolver struct {
-	Interpreter runtime.Interpreter
-	Scopes      ScopeStack
-	CurrentSope fnScope
+	Interpreter  runtime.Interpreter
+	Scopes       ScopeStack
+	CurrentSope  fnScope
+	CurrentClass classScope
+	LoopDepth    int
+	// Labels are the labeled loops around the code being resolved, the innermost last
+	Labels []*Label
+	// GlobalConsts are the top-level constants, globals aren't kept in Scopes
+	GlobalConsts map[string]*def.Var
+	// Enums are the members of the enums declared so far, by enum name
+	Enums map[string][]string
+	// chained are the else-if branches already checked with the if that starts their chain
+	chained map[*def.If]bool
+}
+
+// Label is a labeled loop, it must be used by a break or a continue.
+// Failed is set once an error about the label was reported, it isn't reported as unused then
+type Label struct {
+	Name   def.Token
+	Used   bool
+	Failed bool
 }
 
 // NewResolver creates new instance of resolver
 func NewResolver(i runtime.Interpreter) (r *Resolver) {
 	return &Resolver{
-		Interpreter: i,
-		Scopes:      ScopeStack{},
-		CurrentSope: ScopeNone,
+		Interpreter:  i,
+		Scopes:       ScopeStack{},
+		CurrentSope:  ScopeNone,
+		CurrentClass: ClassNone,
+		GlobalConsts: map[string]*def.Var{},
+		Enums:        map[string][]string{},
+		chained:      map[*def.If]bool{},
 	}
 }
 
@@ -40,7 +75,18 @@ func (r *Resolver) VisitBlock(block *def.Block) *def.RuntimeError {
 
 // VisitVar Handles Var
 func (r *Resolver) VisitVar(varStmt *def.Var) *def.RuntimeError {
-	r.declare(varStmt.Name)
+	if varStmt.Const && r.Scopes.IsEmpty() {
+		// global constants are collected before resolving, so only another one can clash
+		if r.GlobalConsts[varStmt.Name.Lexeme] != varStmt {
+			def.CreateError(varStmt.Name, "Already a constant with this name.")
+		}
+	} else {
+		r.declare(varStmt.Name)
+	}
+	if varStmt.Const && !r.Scopes.IsEmpty() {
+		scope, _ := r.Scopes.Peek()
+		scope[varStmt.Name.Lexeme].IsConst = true
+	}
 	if varStmt.Initializer != nil {
 		err := r.resolveExpr(varStmt.Initializer)
 		if err != nil {
@@ -51,6 +97,25 @@ func (r *Resolver) VisitVar(varStmt *def.Var) *def.RuntimeError {
 	return nil
 }
 
+// VisitDestructure Handles var (x, y) = value;, each name gets its slot in declaration order
+func (r *Resolver) VisitDestructure(destructure *def.Destructure) *def.RuntimeError {
+	names := destructure.Names
+	if destructure.Rest != nil {
+		names = append(append([]def.Token{}, names...), *destructure.Rest)
+	}
+	for _, name := range names {
+		r.declare(name)
+	}
+	err := r.resolveExpr(destructure.Initializer)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		r.define(name)
+	}
+	return nil
+}
+
 // VisitVariableExpr Handles ExprStmt
 func (r *Resolver) VisitVariableExpr(variable *def.Variable) (interface{}, *def.RuntimeError) {
 	notIsEmpty := !r.Scopes.IsEmpty()
@@ -73,11 +138,28 @@ func (r *Resolver) VisitAssignExpr(assign *def.Assign) (interface{}, *def.Runtim
 	if err != nil {
 		return nil, err
 	}
+	if r.isConstant(assign.Name) {
+		return nil, &def.RuntimeError{
+			Token:   assign.Name,
+			Message: fmt.Sprintf("Can't assign to constant '%s'.", assign.Name.Lexeme),
+		}
+	}
 	r.resolveLocal(assign, assign.Name)
 
 	return nil, nil
 }
 
+// isConstant checks if the closest variable with the name was declared with const or let
+func (r *Resolver) isConstant(name def.Token) bool {
+	for i := len(r.Scopes) - 1; i >= 0; i-- {
+		if variable, ok := r.Scopes[i][name.Lexeme]; ok {
+			return variable.IsConst
+		}
+	}
+	_, ok := r.GlobalConsts[name.Lexeme]
+	return ok
+}
+
 // VisitFunction Handles Function
 func (r *Resolver) VisitFunction(function *def.Function) *def.RuntimeError {
 	r.declare(function.Name)
@@ -86,6 +168,227 @@ func (r *Resolver) VisitFunction(function *def.Function) *def.RuntimeError {
 	return nil
 }
 
+// VisitEnum Handles Enum declarations, the members are kept to check if/else if chains
+func (r *Resolver) VisitEnum(enum *def.Enum) *def.RuntimeError {
+	r.declare(enum.Name)
+	r.define(enum.Name)
+	members := make([]string, len(enum.Members))
+	for idx, member := range enum.Members {
+		members[idx] = member.Lexeme
+	}
+	r.Enums[enum.Name.Lexeme] = members
+	return nil
+}
+
+// VisitClass Handles Class declarations
+func (r *Resolver) VisitClass(class *def.Class) *def.RuntimeError {
+	enclosingClass := r.CurrentClass
+	r.CurrentClass = ClassClass
+	defer func() { r.CurrentClass = enclosingClass }()
+
+	r.declare(class.Name)
+	r.define(class.Name)
+
+	if class.Superclass != nil {
+		if class.Superclass.Name.Lexeme == class.Name.Lexeme {
+			return &def.RuntimeError{
+				Token:   class.Superclass.Name,
+				Message: "A class can't inherit from itself",
+			}
+		}
+		r.CurrentClass = ClassSubclass
+		err := r.resolveExpr(class.Superclass)
+		if err != nil {
+			return err
+		}
+		r.beginScope()
+		scope, _ := r.Scopes.Peek()
+		scope["super"] = &Variable{
+			IsDefined: true,
+			Slot:      0,
+		}
+		defer r.endScope()
+	}
+
+	r.beginScope()
+	scope, _ := r.Scopes.Peek()
+	scope["this"] = &Variable{
+		IsDefined: true,
+		Slot:      0,
+	}
+	for _, method := range class.Methods {
+		declaration := ScopeMethod
+		if method.Name.Lexeme == "init" {
+			declaration = ScopeInitializer
+		}
+		r.resolveFunction(method.FuncExpr, declaration)
+	}
+	r.endScope()
+	return nil
+}
+
+// VisitThrow Handles Throw
+func (r *Resolver) VisitThrow(throw *def.Throw) *def.RuntimeError {
+	return r.resolveExpr(throw.Value)
+}
+
+// VisitTry Handles try/catch/finally, the exception variable shares the scope of the catch body
+func (r *Resolver) VisitTry(try *def.Try) *def.RuntimeError {
+	r.beginScope()
+	r.ResolveStmts(try.Body)
+	r.endScope()
+
+	if try.CatchBody != nil {
+		r.beginScope()
+		r.declare(try.CatchName)
+		r.define(try.CatchName)
+		r.ResolveStmts(try.CatchBody)
+		r.endScope()
+	}
+
+	if try.FinallyBody != nil {
+		r.beginScope()
+		r.ResolveStmts(try.FinallyBody)
+		r.endScope()
+	}
+	return nil
+}
+
+// VisitImport Handles Import, it declares the module alias or the imported names
+func (r *Resolver) VisitImport(importStmt *def.Import) *def.RuntimeError {
+	if len(importStmt.Names) == 0 {
+		r.declare(importStmt.Alias)
+		r.define(importStmt.Alias)
+		return nil
+	}
+	for _, name := range importStmt.Names {
+		r.declare(name)
+		r.define(name)
+	}
+	return nil
+}
+
+// VisitMatch Handles Match, each case has its own scope for the names bound by its patterns
+func (r *Resolver) VisitMatch(match *def.Match) *def.RuntimeError {
+	err := r.resolveExpr(match.Subject)
+	if err != nil {
+		return err
+	}
+	unreachable := false
+	for _, matchCase := range match.Cases {
+		if unreachable {
+			def.Warning(matchCase.Keyword.Line, "Unreachable case after a case that matches everything")
+		}
+		if len(matchCase.Patterns) > 1 && r.bindsNames(matchCase.Patterns) {
+			return &def.RuntimeError{
+				Token:   matchCase.Keyword,
+				Message: "Can't bind names in a case with many patterns",
+			}
+		}
+
+		r.beginScope()
+		for _, pattern := range matchCase.Patterns {
+			if err == nil {
+				err = r.declarePattern(pattern)
+			}
+		}
+		if err == nil && matchCase.Guard != nil {
+			err = r.resolveExpr(matchCase.Guard)
+		}
+		if err == nil {
+			err = r.resolveStmt(matchCase.Body)
+		}
+		r.endScope()
+		if err != nil {
+			return err
+		}
+
+		if matchCase.Guard == nil {
+			for _, pattern := range matchCase.Patterns {
+				if pattern.Kind == def.PATTERNWILDCARD || pattern.Kind == def.PATTERNBIND {
+					unreachable = true
+				}
+			}
+		}
+	}
+	if !unreachable {
+		r.checkEnumMatch(match)
+	}
+	return nil
+}
+
+// declarePattern declares the bound names in the same order the interpreter defines them,
+// and resolves the qualified names, which are evaluated in the case scope
+func (r *Resolver) declarePattern(pattern *def.Pattern) *def.RuntimeError {
+	switch pattern.Kind {
+	case def.PATTERNBIND:
+		r.declare(pattern.Token)
+		r.define(pattern.Token)
+	case def.PATTERNMEMBER:
+		return r.resolveExpr(pattern.Member)
+	case def.PATTERNLIST, def.PATTERNMAP:
+		for _, element := range pattern.Elements {
+			err := r.declarePattern(element)
+			if err != nil {
+				return err
+			}
+		}
+		if pattern.Rest != nil {
+			r.declare(*pattern.Rest)
+			r.define(*pattern.Rest)
+		}
+	}
+	return nil
+}
+
+// checkEnumMatch warns when every case of a match without a catch-all case has members
+// of one enum as patterns, and some member is left out. Cases with a guard cover nothing
+func (r *Resolver) checkEnumMatch(match *def.Match) {
+	enum := ""
+	covered := map[string]bool{}
+	for _, matchCase := range match.Cases {
+		for _, pattern := range matchCase.Patterns {
+			if pattern.Kind != def.PATTERNMEMBER || !r.isEnumMember(pattern.Member) {
+				return
+			}
+			name := pattern.Member.Object.(*def.Variable).Name.Lexeme
+			if enum != "" && enum != name {
+				return
+			}
+			enum = name
+			if matchCase.Guard == nil {
+				covered[pattern.Member.Name.Lexeme] = true
+			}
+		}
+	}
+	if enum == "" {
+		return
+	}
+	missing := []string{}
+	for _, member := range r.Enums[enum] {
+		if !covered[member] {
+			missing = append(missing, enum+"."+member)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	subject, ok := describeSubject(match.Subject)
+	if !ok {
+		subject = "the value"
+	}
+	def.Warning(match.Keyword.Line, fmt.Sprintf("The match on %s doesn't handle %s", subject, strings.Join(missing, ", ")))
+}
+
+func (r *Resolver) bindsNames(patterns []*def.Pattern) bool {
+	for _, pattern := range patterns {
+		if pattern.Kind == def.PATTERNBIND || pattern.Rest != nil || r.bindsNames(pattern.Elements) {
+			return true
+		}
+	}
+	return false
+}
+
 // VisitExpressionStmt Handles ExprStmt
 func (r *Resolver) VisitExpressionStmt(exprStmt *def.ExprStmt) *def.RuntimeError {
 	err := r.resolveExpr(exprStmt.Expr)
@@ -97,6 +400,9 @@ func (r *Resolver) VisitExpressionStmt(exprStmt *def.ExprStmt) *def.RuntimeError
 
 // VisitIf Handles Grouping
 func (r *Resolver) VisitIf(ifStmt *def.If) *def.RuntimeError {
+	if !r.chained[ifStmt] {
+		r.checkEnumChain(ifStmt)
+	}
 	err := r.resolveExpr(ifStmt.Condition)
 	if err != nil {
 		return err
@@ -114,23 +420,142 @@ func (r *Resolver) VisitIf(ifStmt *def.If) *def.RuntimeError {
 	return nil
 }
 
+// enumComparison is what a chain of if/else if compares against enum members
+type enumComparison struct {
+	enum    string
+	subject string
+	line    int
+	covered map[string]bool
+}
+
+// checkEnumChain warns when every branch of an if/else if chain compares the same value
+// with members of one enum, there is no final else, and some member is left out
+func (r *Resolver) checkEnumChain(ifStmt *def.If) {
+	comparison := &enumComparison{covered: map[string]bool{}}
+	branches := 0
+	for stmt := ifStmt; stmt != nil; {
+		if !r.collectEnumComparisons(stmt.Condition, comparison) {
+			return
+		}
+		branches++
+		next, isIf := stmt.ElseBranch.(*def.If)
+		if stmt.ElseBranch != nil && !isIf {
+			// the final else takes the members left
+			return
+		}
+		if isIf {
+			r.chained[next] = true
+		}
+		stmt = next
+	}
+	if branches < 2 {
+		return
+	}
+	missing := []string{}
+	for _, member := range r.Enums[comparison.enum] {
+		if !comparison.covered[member] {
+			missing = append(missing, comparison.enum+"."+member)
+		}
+	}
+	if len(missing) > 0 {
+		def.Warning(comparison.line, fmt.Sprintf("The if chain on %s doesn't handle %s", comparison.subject, strings.Join(missing, ", ")))
+	}
+}
+
+// collectEnumComparisons accepts conditions like c == Color.Red or c == Color.Red or c == Color.Blue
+func (r *Resolver) collectEnumComparisons(condition def.Expr, comparison *enumComparison) bool {
+	if logical, ok := condition.(*def.Logical); ok && logical.Operator.Type == def.OR {
+		return r.collectEnumComparisons(logical.Left, comparison) && r.collectEnumComparisons(logical.Right, comparison)
+	}
+	binary, ok := condition.(*def.Binary)
+	if !ok || binary.Token.Type != def.EQUALEQUAL {
+		return false
+	}
+	member, memberOk := binary.Right.(*def.Get)
+	subject := binary.Left
+	if !memberOk || !r.isEnumMember(member) {
+		member, memberOk = binary.Left.(*def.Get)
+		subject = binary.Right
+		if !memberOk || !r.isEnumMember(member) {
+			return false
+		}
+	}
+	subjectName, ok := describeSubject(subject)
+	if !ok {
+		return false
+	}
+	enum := member.Object.(*def.Variable).Name.Lexeme
+	if comparison.enum == "" {
+		comparison.enum = enum
+		comparison.subject = subjectName
+		comparison.line = member.Name.Line
+	}
+	if comparison.enum != enum || comparison.subject != subjectName {
+		return false
+	}
+	comparison.covered[member.Name.Lexeme] = true
+	return true
+}
+
+func (r *Resolver) isEnumMember(get *def.Get) bool {
+	enum, ok := get.Object.(*def.Variable)
+	if !ok {
+		return false
+	}
+	for _, member := range r.Enums[enum.Name.Lexeme] {
+		if member == get.Name.Lexeme {
+			return true
+		}
+	}
+	return false
+}
+
+// describeSubject names the value compared in the chain, only variables and their properties are followed
+func describeSubject(expr def.Expr) (string, bool) {
+	switch subject := expr.(type) {
+	case *def.Variable:
+		return subject.Name.Lexeme, true
+	case *def.This:
+		return "this", true
+	case *def.Get:
+		object, ok := describeSubject(subject.Object)
+		return object + "." + subject.Name.Lexeme, ok
+	}
+	return "", false
+}
+
 func (r *Resolver) beginScope() {
 	r.Scopes.Push(map[string]*Variable{})
 }
 
 // ResolveStmts resolve all statements
 func (r *Resolver) ResolveStmts(stmts []def.Stmt) {
+	if r.Scopes.IsEmpty() {
+		r.collectGlobalConsts(stmts)
+	}
 	for _, s := range stmts {
 		err := func(stmt def.Stmt) *def.RuntimeError {
 			return r.resolveStmt(stmt)
 		}(s)
 		if err != nil {
-			def.ReportRuntimeError(err)
+			// static errors are reported as compile errors, so the program never runs
+			def.LogError(err.Token.Line, err.Message)
 			return
 		}
 	}
 }
 
+// collectGlobalConsts finds the top-level constants first, so functions declared before them can't assign them
+func (r *Resolver) collectGlobalConsts(stmts []def.Stmt) {
+	for _, stmt := range stmts {
+		if varStmt, ok := stmt.(*def.Var); ok && varStmt.Const {
+			if _, found := r.GlobalConsts[varStmt.Name.Lexeme]; !found {
+				r.GlobalConsts[varStmt.Name.Lexeme] = varStmt
+			}
+		}
+	}
+}
+
 func (r *Resolver) resolveStmt(stmt def.Stmt) *def.RuntimeError {
 	return stmt.Accept(r)
 }
@@ -145,17 +570,38 @@ func (r *Resolver) resolveExpr(expr def.Expr) *def.RuntimeError {
 
 func (r *Resolver) resolveFunction(function def.FunctionExpr, scope fnScope) {
 	enclosingScope := r.CurrentSope
+	enclosingLoopDepth := r.LoopDepth
+	enclosingLabels := r.Labels
 	r.CurrentSope = scope
+	if function.IsGenerator && scope != ScopeInitializer {
+		// the values of a generator are its yields, a return can only stop it
+		r.CurrentSope = ScopeGenerator
+	}
+	// loops don't cross function boundaries
+	r.LoopDepth = 0
+	r.Labels = nil
 	r.beginScope()
-	for _, p := range function.Params {
+	for idx, p := range function.Params {
+		// defaults run in the function scope, seeing only the params before them
+		if function.Defaults[idx] != nil {
+			if err := r.resolveExpr(function.Defaults[idx]); err != nil {
+				def.LogError(err.Token.Line, err.Message)
+			}
+		}
 		func(param def.Token) {
 			r.declare(param)
 			r.define(param)
 		}(p)
 	}
+	if function.Rest != nil {
+		r.declare(*function.Rest)
+		r.define(*function.Rest)
+	}
 	r.ResolveStmts(function.Body)
 	r.endScope()
 	r.CurrentSope = enclosingScope
+	r.LoopDepth = enclosingLoopDepth
+	r.Labels = enclosingLabels
 }
 
 func (r *Resolver) resolveLocal(expr def.Expr, token def.Token) {
@@ -171,6 +617,9 @@ func (r *Resolver) resolveLocal(expr def.Expr, token def.Token) {
 
 func (r *Resolver) declare(token def.Token) {
 	if r.Scopes.IsEmpty() {
+		if _, ok := r.GlobalConsts[token.Lexeme]; ok {
+			def.CreateError(token, "Already a constant with this name.")
+		}
 		return
 	}
 	scope, _ := r.Scopes.Peek()
@@ -199,8 +648,7 @@ func (r *Resolver) endScope() {
 
 // VisitPrintStmt Handles Print
 func (r *Resolver) VisitPrintStmt(print *def.Print) *def.RuntimeError {
-	r.resolveExpr(print.Expr)
-	return nil
+	return r.resolveExpr(print.Expr)
 }
 
 // VisitWhile Handles Grouping
@@ -209,15 +657,105 @@ func (r *Resolver) VisitWhile(whileStmt *def.While) *def.RuntimeError {
 	if err != nil {
 		return err
 	}
-	err = r.resolveStmt(whileStmt.Body)
+	r.LoopDepth++
+	defer func() { r.LoopDepth-- }()
+	err = r.resolveLabeled(whileStmt.Label, whileStmt.Body)
+	if err != nil {
+		return err
+	}
+	if whileStmt.Increment != nil {
+		return r.resolveExpr(whileStmt.Increment)
+	}
+	return nil
+}
+
+// VisitForIn Handles for-in loops, the loop variable lives in its own scope
+func (r *Resolver) VisitForIn(forIn *def.ForIn) *def.RuntimeError {
+	err := r.resolveExpr(forIn.Iterable)
 	if err != nil {
 		return err
 	}
+	r.LoopDepth++
+	defer func() { r.LoopDepth-- }()
+	r.beginScope()
+	defer r.endScope()
+	r.declare(forIn.Name)
+	r.define(forIn.Name)
+	return r.resolveLabeled(forIn.Label, forIn.Body)
+}
+
+// resolveLabeled resolves the body of a loop with its label, which is always ended,
+// but only reported as unused when the body resolved without errors
+func (r *Resolver) resolveLabeled(name *def.Token, body def.Stmt) *def.RuntimeError {
+	labelErr := r.beginLabel(name)
+	bodyErr := r.resolveStmt(body)
+	endErr := r.endLabel(name, labelErr == nil && bodyErr == nil)
+	if labelErr != nil {
+		return labelErr
+	}
+	if bodyErr != nil {
+		return bodyErr
+	}
+	return endErr
+}
+
+func (r *Resolver) beginLabel(name *def.Token) *def.RuntimeError {
+	if name == nil {
+		return nil
+	}
+	for _, label := range r.Labels {
+		if label.Name.Lexeme == name.Lexeme {
+			// still pushed, so endLabel always has a label to pop
+			r.Labels = append(r.Labels, &Label{Name: *name, Failed: true})
+			return &def.RuntimeError{
+				Token:   *name,
+				Message: fmt.Sprintf("Label '%s' is already used by an enclosing loop", name.Lexeme),
+			}
+		}
+	}
+	r.Labels = append(r.Labels, &Label{Name: *name})
+	return nil
+}
+
+func (r *Resolver) endLabel(name *def.Token, report bool) *def.RuntimeError {
+	if name == nil {
+		return nil
+	}
+	label := r.Labels[len(r.Labels)-1]
+	r.Labels = r.Labels[:len(r.Labels)-1]
+	if report && !label.Used && !label.Failed {
+		return &def.RuntimeError{
+			Token:   label.Name,
+			Message: fmt.Sprintf("Label '%s' is never used", label.Name.Lexeme),
+		}
+	}
 	return nil
 }
 
-// VisitControlFlow Handles Grouping
+// VisitControlFlow Handles break and continue, which are only valid inside loops
 func (r *Resolver) VisitControlFlow(controlFlow *def.ControlFlow) *def.RuntimeError {
+	if r.LoopDepth == 0 {
+		return &def.RuntimeError{
+			Token:   controlFlow.Keyword,
+			Message: fmt.Sprintf("Can't use '%s' outside of a loop", controlFlow.Keyword.Lexeme),
+		}
+	}
+	if controlFlow.Label != nil {
+		for _, label := range r.Labels {
+			if label.Name.Lexeme == controlFlow.Label.Lexeme {
+				label.Used = true
+				return nil
+			}
+		}
+		// the label was likely meant for one of the enclosing ones, don't report them as unused too
+		for _, label := range r.Labels {
+			label.Failed = true
+		}
+		return &def.RuntimeError{
+			Token:   *controlFlow.Label,
+			Message: fmt.Sprintf("Unknown label '%s'", controlFlow.Label.Lexeme),
+		}
+	}
 	return nil
 }
 
@@ -236,6 +774,18 @@ func (r *Resolver) VisitReturnStmt(returnStmt *def.Return) *def.RuntimeError {
 		}
 	}
 	if returnStmt.Value != nil {
+		if r.CurrentSope == ScopeInitializer {
+			return &def.RuntimeError{
+				Token:   returnStmt.Keyword,
+				Message: "Can't return a value from an initializer",
+			}
+		}
+		if r.CurrentSope == ScopeGenerator {
+			return &def.RuntimeError{
+				Token:   returnStmt.Keyword,
+				Message: "Can't return a value from a generator",
+			}
+		}
 		err := r.resolveExpr(returnStmt.Value)
 		if err != nil {
 			return err
@@ -244,6 +794,26 @@ func (r *Resolver) VisitReturnStmt(returnStmt *def.Return) *def.RuntimeError {
 	return nil
 }
 
+// VisitYield Handles Yield, only functions can become generators
+func (r *Resolver) VisitYield(yield *def.Yield) *def.RuntimeError {
+	if r.CurrentSope == ScopeNone {
+		return &def.RuntimeError{
+			Token:   yield.Keyword,
+			Message: "Can't yield from top-level code",
+		}
+	}
+	if r.CurrentSope == ScopeInitializer {
+		return &def.RuntimeError{
+			Token:   yield.Keyword,
+			Message: "Can't yield from an initializer",
+		}
+	}
+	if yield.Value != nil {
+		return r.resolveExpr(yield.Value)
+	}
+	return nil
+}
+
 // VisitLiteralExpr Handles Literal
 func (r *Resolver) VisitLiteralExpr(literal *def.Literal) (interface{}, *def.RuntimeError) {
 	return nil, nil
@@ -251,8 +821,7 @@ func (r *Resolver) VisitLiteralExpr(literal *def.Literal) (interface{}, *def.Run
 
 // VisitGroupingExpr Handles Grouping
 func (r *Resolver) VisitGroupingExpr(grouping *def.Grouping) (interface{}, *def.RuntimeError) {
-	r.resolveExpr(grouping.Expression)
-	return nil, nil
+	return nil, r.resolveExpr(grouping.Expression)
 }
 
 // VisitBinaryExpr Handles Binary
@@ -304,6 +873,181 @@ func (r *Resolver) VisitCallExpr(call *def.Call) (interface{}, *def.RuntimeError
 			return nil, err
 		}
 	}
+	for _, named := range call.NamedArguments {
+		err := r.resolveExpr(named.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// VisitGetExpr Handles property access
+func (r *Resolver) VisitGetExpr(get *def.Get) (interface{}, *def.RuntimeError) {
+	return nil, r.resolveExpr(get.Object)
+}
+
+// VisitOptionalChainExpr Handles chains with a ?.
+func (r *Resolver) VisitOptionalChainExpr(chain *def.OptionalChain) (interface{}, *def.RuntimeError) {
+	return nil, r.resolveExpr(chain.Expr)
+}
+
+// VisitSetExpr Handles property assign
+func (r *Resolver) VisitSetExpr(set *def.Set) (interface{}, *def.RuntimeError) {
+	err := r.resolveExpr(set.Value)
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.resolveExpr(set.Object)
+}
+
+// VisitThisExpr Handles 'this' inside methods
+func (r *Resolver) VisitThisExpr(this *def.This) (interface{}, *def.RuntimeError) {
+	if r.CurrentClass == ClassNone {
+		return nil, &def.RuntimeError{
+			Token:   this.Keyword,
+			Message: "Can't use 'this' outside of a class",
+		}
+	}
+	r.resolveLocal(this, this.Keyword)
+	return nil, nil
+}
+
+// VisitSuperExpr Handles 'super' method access
+func (r *Resolver) VisitSuperExpr(super *def.Super) (interface{}, *def.RuntimeError) {
+	if r.CurrentClass == ClassNone {
+		return nil, &def.RuntimeError{
+			Token:   super.Keyword,
+			Message: "Can't use 'super' outside of a class",
+		}
+	}
+	if r.CurrentClass != ClassSubclass {
+		return nil, &def.RuntimeError{
+			Token:   super.Keyword,
+			Message: "Can't use 'super' in a class with no superclass",
+		}
+	}
+	r.resolveLocal(super, super.Keyword)
+	return nil, nil
+}
+
+// VisitListLiteralExpr Handles list literals
+func (r *Resolver) VisitListLiteralExpr(list *def.ListLiteral) (interface{}, *def.RuntimeError) {
+	for _, element := range list.Elements {
+		err := r.resolveExpr(element)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// VisitTupleExpr Handles tuples
+func (r *Resolver) VisitTupleExpr(tuple *def.Tuple) (interface{}, *def.RuntimeError) {
+	for _, element := range tuple.Elements {
+		err := r.resolveExpr(element)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// VisitIndexExpr Handles index access
+func (r *Resolver) VisitIndexExpr(index *def.Index) (interface{}, *def.RuntimeError) {
+	err := r.resolveExpr(index.Object)
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.resolveExpr(index.Index)
+}
+
+// VisitIndexSetExpr Handles index assign
+func (r *Resolver) VisitIndexSetExpr(indexSet *def.IndexSet) (interface{}, *def.RuntimeError) {
+	err := r.resolveExpr(indexSet.Value)
+	if err != nil {
+		return nil, err
+	}
+	err = r.resolveExpr(indexSet.Object)
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.resolveExpr(indexSet.Index)
+}
+
+// VisitMapLiteralExpr Handles map literals
+func (r *Resolver) VisitMapLiteralExpr(mapLiteral *def.MapLiteral) (interface{}, *def.RuntimeError) {
+	for idx := range mapLiteral.Keys {
+		err := r.resolveExpr(mapLiteral.Keys[idx])
+		if err != nil {
+			return nil, err
+		}
+		err = r.resolveExpr(mapLiteral.Values[idx])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
 
+// VisitInterpolationExpr Handles string interpolation
+func (r *Resolver) VisitInterpolationExpr(interpolation *def.Interpolation) (interface{}, *def.RuntimeError) {
+	for _, part := range interpolation.Parts {
+		err := r.resolveExpr(part)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return nil, nil
 }
+
+// VisitCompoundAssignExpr Handles compound assigns, like +=
+func (r *Resolver) VisitCompoundAssignExpr(compound *def.CompoundAssign) (interface{}, *def.RuntimeError) {
+	err := r.resolveExpr(compound.Value)
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.resolveTarget(compound, compound.Target)
+}
+
+// VisitIncrementExpr Handles ++ and --
+func (r *Resolver) VisitIncrementExpr(increment *def.Increment) (interface{}, *def.RuntimeError) {
+	return nil, r.resolveTarget(increment, increment.Target)
+}
+
+// resolveTarget resolves the target of an update, variables are recorded under the update node itself
+func (r *Resolver) resolveTarget(update def.Expr, target def.Expr) *def.RuntimeError {
+	switch t := target.(type) {
+	case *def.Variable:
+		if r.isConstant(t.Name) {
+			return &def.RuntimeError{
+				Token:   t.Name,
+				Message: fmt.Sprintf("Can't assign to constant '%s'.", t.Name.Lexeme),
+			}
+		}
+		r.resolveLocal(update, t.Name)
+	case *def.Get:
+		return r.resolveExpr(t.Object)
+	case *def.Index:
+		err := r.resolveExpr(t.Object)
+		if err != nil {
+			return err
+		}
+		return r.resolveExpr(t.Index)
+	}
+	return nil
+}
+
+// VisitConditionalExpr Handles the ternary operator
+func (r *Resolver) VisitConditionalExpr(conditional *def.Conditional) (interface{}, *def.RuntimeError) {
+	err := r.resolveExpr(conditional.Condition)
+	if err != nil {
+		return nil, err
+	}
+	err = r.resolveExpr(conditional.Then)
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.resolveExpr(conditional.Else)
+}
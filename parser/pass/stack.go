@@ -5,6 +5,7 @@ package pass
 // Variable represents the stack of a variable
 type Variable struct {
 	IsDefined bool
+	IsConst   bool
 	Slot      int
 }
 
@@ -49,7 +49,7 @@ func (astPrinter *AstPrinter) VisitAssignExprStr(assign *def.Assign) string {
 
 // VisitLogicalExprStr Handles Logical
 func (astPrinter *AstPrinter) VisitLogicalExprStr(logical *def.Logical) string {
-	return "TODO"
+	return astPrinter.parenthesize(logical.Operator.Lexeme, logical.Left, logical.Right)
 }
 
 // VisitCallExpr Handles Call
@@ -62,6 +62,86 @@ func (astPrinter *AstPrinter) VisitFunctionExpr(fnExpr *def.FunctionExpr) string
 	return "TODO"
 }
 
+// VisitGetExprStr Handles Get
+func (astPrinter *AstPrinter) VisitGetExprStr(get *def.Get) string {
+	if get.Optional {
+		return astPrinter.parenthesize("get? "+get.Name.Lexeme, get.Object)
+	}
+	return astPrinter.parenthesize("get "+get.Name.Lexeme, get.Object)
+}
+
+// VisitOptionalChainExprStr Handles OptionalChain
+func (astPrinter *AstPrinter) VisitOptionalChainExprStr(chain *def.OptionalChain) string {
+	return astPrinter.parenthesize("?.", chain.Expr)
+}
+
+// VisitSetExprStr Handles Set
+func (astPrinter *AstPrinter) VisitSetExprStr(set *def.Set) string {
+	return astPrinter.parenthesize("set "+set.Name.Lexeme, set.Object, set.Value)
+}
+
+// VisitThisExprStr Handles This
+func (astPrinter *AstPrinter) VisitThisExprStr(this *def.This) string {
+	return "this"
+}
+
+// VisitSuperExprStr Handles Super
+func (astPrinter *AstPrinter) VisitSuperExprStr(super *def.Super) string {
+	return "(super " + super.Method.Lexeme + ")"
+}
+
+// VisitListLiteralExprStr Handles ListLiteral
+func (astPrinter *AstPrinter) VisitListLiteralExprStr(list *def.ListLiteral) string {
+	return astPrinter.parenthesize("list", list.Elements...)
+}
+
+// VisitTupleExprStr Handles Tuple
+func (astPrinter *AstPrinter) VisitTupleExprStr(tuple *def.Tuple) string {
+	return astPrinter.parenthesize("tuple", tuple.Elements...)
+}
+
+// VisitIndexExprStr Handles Index
+func (astPrinter *AstPrinter) VisitIndexExprStr(index *def.Index) string {
+	return astPrinter.parenthesize("index", index.Object, index.Index)
+}
+
+// VisitIndexSetExprStr Handles IndexSet
+func (astPrinter *AstPrinter) VisitIndexSetExprStr(indexSet *def.IndexSet) string {
+	return astPrinter.parenthesize("index-set", indexSet.Object, indexSet.Index, indexSet.Value)
+}
+
+// VisitMapLiteralExprStr Handles MapLiteral
+func (astPrinter *AstPrinter) VisitMapLiteralExprStr(mapLiteral *def.MapLiteral) string {
+	exprs := []def.Expr{}
+	for idx := range mapLiteral.Keys {
+		exprs = append(exprs, mapLiteral.Keys[idx], mapLiteral.Values[idx])
+	}
+	return astPrinter.parenthesize("map", exprs...)
+}
+
+// VisitInterpolationExprStr Handles Interpolation
+func (astPrinter *AstPrinter) VisitInterpolationExprStr(interpolation *def.Interpolation) string {
+	return astPrinter.parenthesize("interpolation", interpolation.Parts...)
+}
+
+// VisitCompoundAssignExprStr Handles CompoundAssign
+func (astPrinter *AstPrinter) VisitCompoundAssignExprStr(compound *def.CompoundAssign) string {
+	return astPrinter.parenthesize(compound.Operator.Lexeme, compound.Target, compound.Value)
+}
+
+// VisitIncrementExprStr Handles Increment
+func (astPrinter *AstPrinter) VisitIncrementExprStr(increment *def.Increment) string {
+	if increment.Prefix {
+		return astPrinter.parenthesize(increment.Operator.Lexeme+"pre", increment.Target)
+	}
+	return astPrinter.parenthesize(increment.Operator.Lexeme+"post", increment.Target)
+}
+
+// VisitConditionalExprStr Handles Conditional
+func (astPrinter *AstPrinter) VisitConditionalExprStr(conditional *def.Conditional) string {
+	return astPrinter.parenthesize("?:", conditional.Condition, conditional.Then, conditional.Else)
+}
+
 func (astPrinter *AstPrinter) parenthesize(name string, exprs ...def.Expr) string {
 	var result string
 	result += "(" + name
@@ -0,0 +1,268 @@
+package runtime
+
+import (
+	"fmt"
+	"loxlang/parser/def"
+	goruntime "runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// LoxGenerator is returned by calling a function that yields. Its body runs on its own
+// goroutine, handing each value over and waiting until the next one is asked
+type LoxGenerator struct {
+	*generator
+}
+
+// generator is kept apart from LoxGenerator, so the goroutine doesn't keep the value alive
+// and the finalizer can queue it to be closed once nobody can ask for values anymore
+type generator struct {
+	name    string
+	set     *generatorSet
+	resume  chan bool
+	results chan generatorResult
+	run     func()
+	started bool
+	running bool
+	done    bool
+	// closed is only used by the goroutine, once resume gave false
+	closed bool
+}
+
+type generatorResult struct {
+	value interface{}
+	done  bool
+	err   *def.RuntimeError
+}
+
+// generatorSet keeps the generators of a program that haven't finished, so they can be closed
+// when it ends. Finalizers run on their own goroutine, they only queue the generators to close
+type generatorSet struct {
+	mutex     sync.Mutex
+	open      map[*generator]int
+	created   int
+	abandoned []*generator
+	pending   int32
+}
+
+// openGenerators gives the set shared by the interpreter and its copies
+func (i *Interpreter) openGenerators() *generatorSet {
+	if i.generators == nil {
+		i.generators = &generatorSet{open: map[*generator]int{}}
+	}
+	return i.generators
+}
+
+func (set *generatorSet) add(g *generator) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	set.created++
+	set.open[g] = set.created
+}
+
+func (set *generatorSet) remove(g *generator) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	delete(set.open, g)
+}
+
+func (set *generatorSet) abandon(g *generator) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+	set.abandoned = append(set.abandoned, g)
+	atomic.StoreInt32(&set.pending, 1)
+}
+
+// closeAbandoned closes the generators queued by finalizers, it runs between statements
+// so their finally blocks never run alongside the rest of the program
+func (set *generatorSet) closeAbandoned() {
+	if atomic.LoadInt32(&set.pending) == 0 {
+		return
+	}
+	set.mutex.Lock()
+	abandoned := set.abandoned
+	set.abandoned = nil
+	atomic.StoreInt32(&set.pending, 0)
+	set.mutex.Unlock()
+	for _, g := range abandoned {
+		if g.running {
+			// the handle can be collected while its body runs, close it once it yields
+			set.abandon(g)
+			continue
+		}
+		// nobody can see errors of a generator that is no longer used
+		g.close()
+	}
+}
+
+// CloseGenerators closes the generators that haven't finished, the last created first,
+// so their finally blocks run before the program ends. It gives back the first error
+func (i *Interpreter) CloseGenerators() *def.RuntimeError {
+	if i.generators == nil {
+		return nil
+	}
+	set := i.generators
+	set.mutex.Lock()
+	open := make([]*generator, 0, len(set.open))
+	for g := range set.open {
+		open = append(open, g)
+	}
+	set.mutex.Unlock()
+	sort.Slice(open, func(a, b int) bool { return set.open[open[a]] > set.open[open[b]] })
+
+	var err *def.RuntimeError
+	for _, g := range open {
+		err = firstError(err, g.close())
+	}
+	return err
+}
+
+// NewGenerator creates the generator for a call of f, with the params already defined in env
+func NewGenerator(i *Interpreter, f *CallableFunction, env *Environment) *LoxGenerator {
+	set := i.openGenerators()
+	state := &generator{
+		name:    f.Name,
+		set:     set,
+		resume:  make(chan bool),
+		results: make(chan generatorResult),
+	}
+	// the body gets its own interpreter, pausing it can't leave the caller in the body environment
+	body := *i
+	// not even the caller environment, it may hold this generator
+	body.Env = nil
+	body.generator = state
+	state.run = func() {
+		<-state.resume
+		err := body.executeBlock(f.FunctionExpr.Body, env)
+		if err != nil && (err.Type == def.RETURNSTMT || err.Type == def.GENERATORCLOSED) {
+			err = nil
+		}
+		state.results <- generatorResult{done: true, err: err}
+	}
+	set.add(state)
+
+	handle := &LoxGenerator{state}
+	goruntime.SetFinalizer(handle, func(g *LoxGenerator) { set.abandon(g.generator) })
+	return handle
+}
+
+// String shows the function that created the generator
+func (g *LoxGenerator) String() string {
+	if g.name != "" {
+		return fmt.Sprintf("<generator %s>", g.name)
+	}
+	return "<generator>"
+}
+
+// Next runs the body until the next yield, ok is false once the body is over
+func (g *LoxGenerator) Next(i *Interpreter) (interface{}, bool, *def.RuntimeError) {
+	if g.done {
+		return nil, false, nil
+	}
+	if g.running {
+		return nil, false, alreadyRunning()
+	}
+	if !g.started {
+		g.started = true
+		go g.run()
+	}
+	result := g.resumeBody(true)
+	if result.done {
+		return nil, false, result.err
+	}
+	return result.value, true, nil
+}
+
+// Get returns the methods of the generator
+func (g *LoxGenerator) Get(name def.Token) (interface{}, *def.RuntimeError) {
+	switch name.Lexeme {
+	case "next":
+		return &GeneratorNextCallable{Generator: g}, nil
+	case "close":
+		return &GeneratorCloseCallable{Generator: g}, nil
+	}
+	return nil, &def.RuntimeError{
+		Token:   name,
+		Message: fmt.Sprintf("Undefined property %s.", name.Lexeme),
+	}
+}
+
+// resumeBody lets the body run until its next yield, or until it unwinds when keepGoing is false
+func (g *generator) resumeBody(keepGoing bool) generatorResult {
+	g.running = true
+	g.resume <- keepGoing
+	result := <-g.results
+	g.running = false
+	if result.done {
+		g.done = true
+		g.set.remove(g)
+	}
+	return result
+}
+
+// yield is called by the body, it reports if the generator should keep going
+func (g *generator) yield(value interface{}) bool {
+	if g.closed {
+		return false
+	}
+	g.results <- generatorResult{value: value}
+	if keepGoing := <-g.resume; !keepGoing {
+		g.closed = true
+		return false
+	}
+	return true
+}
+
+// close stops a body paused at a yield and waits while it unwinds, running its finally blocks
+func (g *generator) close() *def.RuntimeError {
+	if g.done {
+		return nil
+	}
+	if g.running {
+		return alreadyRunning()
+	}
+	if !g.started {
+		g.done = true
+		g.set.remove(g)
+		return nil
+	}
+	return g.resumeBody(false).err
+}
+
+func alreadyRunning() *def.RuntimeError {
+	return &def.RuntimeError{
+		Message: "Generator is already running",
+	}
+}
+
+// GeneratorNextCallable is the next method of a generator, nil means there are no more values
+type GeneratorNextCallable struct {
+	Generator *LoxGenerator
+}
+
+// Arity of the next fn
+func (c *GeneratorNextCallable) Arity() Arity {
+	return FixedArity(0)
+}
+
+// Call representation of the next fn
+func (c *GeneratorNextCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	value, _, err := c.Generator.Next(i)
+	return value, err
+}
+
+// GeneratorCloseCallable is the close method of a generator, the body stops where it is paused
+type GeneratorCloseCallable struct {
+	Generator *LoxGenerator
+}
+
+// Arity of the close fn
+func (c *GeneratorCloseCallable) Arity() Arity {
+	return FixedArity(0)
+}
+
+// Call representation of the close fn
+func (c *GeneratorCloseCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	return nil, c.Generator.close()
+}
@@ -0,0 +1,99 @@
+package runtime_test
+
+import (
+	"loxlang/parser"
+	"loxlang/parser/def"
+	"loxlang/parser/lexer"
+	"loxlang/parser/pass"
+	"loxlang/parser/runtime"
+	goruntime "runtime"
+	"testing"
+	"time"
+)
+
+func run(t *testing.T, source string) *runtime.Interpreter {
+	t.Helper()
+	stmts, err := parser.Parse(lexer.ScanFile(source, "test.lox"))
+	if err != nil || def.HadError {
+		def.HadError = false
+		t.Fatalf("parse failed: %v", err)
+	}
+	interpreter := runtime.NewInterpreter()
+	pass.NewResolver(*interpreter).ResolveStmts(stmts)
+	if def.HadError {
+		def.HadError = false
+		t.Fatal("resolve failed")
+	}
+	if runErr := interpreter.Execute(stmts); runErr != nil {
+		t.Fatalf("run failed: %v", runErr)
+	}
+	return interpreter
+}
+
+// waitGoroutines waits for the generator goroutines to exit, they do it right after handing their last result
+func waitGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for goruntime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines alive, want %d", goruntime.NumGoroutine(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGeneratorsClosedByForInAndClose(t *testing.T) {
+	before := goruntime.NumGoroutine()
+	interpreter := run(t, `
+var cleanups = 0;
+fun gen() {
+  try {
+    yield 1;
+    yield 2;
+  } finally {
+    cleanups = cleanups + 1;
+  }
+}
+fun first() {
+  for (var x in gen()) return x;
+}
+for (var i = 0; i < 100; i++) {
+  for (var x in gen()) break;
+  first();
+  var g = gen();
+  g.next();
+  g.close();
+}
+`)
+	waitGoroutines(t, before)
+	if cleanups := interpreter.Globals["cleanups"]; cleanups != int64(300) {
+		t.Errorf("finally ran %v times, want 300", cleanups)
+	}
+}
+
+func TestAbandonedGeneratorsClosedAtTheEnd(t *testing.T) {
+	before := goruntime.NumGoroutine()
+	interpreter := run(t, `
+var cleanups = 0;
+fun outer() {
+  fun g() {
+    try {
+      yield 1;
+      yield 2;
+    } finally {
+      cleanups = cleanups + 1;
+    }
+  }
+  var h = g();
+  h.next();
+}
+for (var i = 0; i < 200; i++) outer();
+`)
+	if err := interpreter.CloseGenerators(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	waitGoroutines(t, before)
+	if cleanups := interpreter.Globals["cleanups"]; cleanups != int64(200) {
+		t.Errorf("finally ran %v times, want 200", cleanups)
+	}
+}
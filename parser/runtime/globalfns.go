@@ -3,17 +3,264 @@ package runtime
 import (
 	"loxlang/parser/def"
 	"time"
+	"unicode/utf8"
 )
 
 // ClockCallable default clock function implementation
 type ClockCallable struct{}
 
 // Arity of the clock fn
-func (c *ClockCallable) Arity() int {
-	return 0
+func (c *ClockCallable) Arity() Arity {
+	return FixedArity(0)
 }
 
 // Call representation of the clock fn
 func (c *ClockCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
-	return time.Now().UnixNano() / int64(time.Millisecond), nil
+	return float64(time.Now().UnixNano() / int64(time.Millisecond)), nil
+}
+
+// LenCallable returns the size of a list, a tuple, a map or a string
+type LenCallable struct{}
+
+// Arity of the len fn
+func (c *LenCallable) Arity() Arity {
+	return FixedArity(1)
+}
+
+// Call representation of the len fn
+func (c *LenCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	switch value := args[0].(type) {
+	case *LoxList:
+		return int64(len(value.Elements)), nil
+	case *LoxTuple:
+		return int64(len(value.Elements)), nil
+	case *LoxMap:
+		return int64(len(value.Keys)), nil
+	case string:
+		return int64(utf8.RuneCountInString(value)), nil
+	}
+	return nil, &def.RuntimeError{
+		Message: "len() expects a list, a tuple, a map or a string",
+	}
+}
+
+// PushCallable appends values to the end of a list
+type PushCallable struct{}
+
+// Arity of the push fn, any number of values can be pushed
+func (c *PushCallable) Arity() Arity {
+	return Arity{Min: 2, Max: Variadic}
+}
+
+// Call representation of the push fn
+func (c *PushCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	list, ok := args[0].(*LoxList)
+	if !ok {
+		return nil, &def.RuntimeError{
+			Message: "push() expects a list",
+		}
+	}
+	list.Elements = append(list.Elements, args[1:]...)
+	return nil, nil
+}
+
+// PopCallable removes and returns a value of a list, the last one by default
+type PopCallable struct{}
+
+// Arity of the pop fn
+func (c *PopCallable) Arity() Arity {
+	return Arity{Min: 1, Max: 2}
+}
+
+// ParamNames of the pop fn
+func (c *PopCallable) ParamNames() []string {
+	return []string{"list", "index"}
+}
+
+// Call representation of the pop fn
+func (c *PopCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	list, ok := args[0].(*LoxList)
+	if !ok {
+		return nil, &def.RuntimeError{
+			Message: "pop() expects a list",
+		}
+	}
+	if len(list.Elements) == 0 {
+		return nil, &def.RuntimeError{
+			Message: "Can't pop from an empty list",
+		}
+	}
+	idx := len(list.Elements) - 1
+	if index, ok := argumentAt(args, 1); ok {
+		// no token, the call fills in where it came from
+		position, err := i.listIndex(def.Token{}, list.Elements, index)
+		if err != nil {
+			return nil, err
+		}
+		idx = position
+	}
+	value := list.Elements[idx]
+	list.Elements = append(list.Elements[:idx], list.Elements[idx+1:]...)
+	return value, nil
+}
+
+// KeysCallable returns the keys of a map as a list
+type KeysCallable struct{}
+
+// Arity of the keys fn
+func (c *KeysCallable) Arity() Arity {
+	return FixedArity(1)
+}
+
+// Call representation of the keys fn
+func (c *KeysCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	m, ok := args[0].(*LoxMap)
+	if !ok {
+		return nil, &def.RuntimeError{
+			Message: "keys() expects a map",
+		}
+	}
+	keys := make([]interface{}, len(m.Keys))
+	copy(keys, m.Keys)
+	return &LoxList{Elements: keys}, nil
+}
+
+// ValuesCallable returns the values of a map as a list
+type ValuesCallable struct{}
+
+// Arity of the values fn
+func (c *ValuesCallable) Arity() Arity {
+	return FixedArity(1)
+}
+
+// Call representation of the values fn
+func (c *ValuesCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	m, ok := args[0].(*LoxMap)
+	if !ok {
+		return nil, &def.RuntimeError{
+			Message: "values() expects a map",
+		}
+	}
+	values := make([]interface{}, len(m.Keys))
+	for idx, key := range m.Keys {
+		values[idx], _ = m.Get(key)
+	}
+	return &LoxList{Elements: values}, nil
+}
+
+// HasCallable checks if a map contains a key
+type HasCallable struct{}
+
+// Arity of the has fn
+func (c *HasCallable) Arity() Arity {
+	return FixedArity(2)
+}
+
+// Call representation of the has fn
+func (c *HasCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	m, ok := args[0].(*LoxMap)
+	if !ok {
+		return nil, &def.RuntimeError{
+			Message: "has() expects a map",
+		}
+	}
+	_, found := m.Get(args[1])
+	return found, nil
+}
+
+// DeleteCallable removes a key from a map, returning if it was present
+type DeleteCallable struct{}
+
+// Arity of the delete fn
+func (c *DeleteCallable) Arity() Arity {
+	return FixedArity(2)
+}
+
+// Call representation of the delete fn
+func (c *DeleteCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	m, ok := args[0].(*LoxMap)
+	if !ok {
+		return nil, &def.RuntimeError{
+			Message: "delete() expects a map",
+		}
+	}
+	return m.Delete(args[1]), nil
+}
+
+// ErrorCallable creates an Error value with a message, to be thrown
+type ErrorCallable struct{}
+
+// Arity of the Error fn
+func (c *ErrorCallable) Arity() Arity {
+	return FixedArity(1)
+}
+
+// ParamNames of the Error fn
+func (c *ErrorCallable) ParamNames() []string {
+	return []string{"message"}
+}
+
+// Call representation of the Error fn
+func (c *ErrorCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	return NewErrorInstance(i.stringfy(args[0]), nil), nil
+}
+
+// RangeCallable creates a range of numbers, from start up to end (exclusive)
+type RangeCallable struct{}
+
+// Arity of the range fn, range(end), range(start, end) or range(start, end, step)
+func (c *RangeCallable) Arity() Arity {
+	return Arity{Min: 1, Max: 3}
+}
+
+// Call representation of the range fn
+func (c *RangeCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	for _, arg := range args {
+		if !isNumber(arg) {
+			return nil, &def.RuntimeError{
+				Message: "range() expects numbers",
+			}
+		}
+	}
+	r := &LoxRange{Start: int64(0), Step: int64(1)}
+	switch len(args) {
+	case 1:
+		r.End = args[0]
+	case 2:
+		r.Start, r.End = args[0], args[1]
+	case 3:
+		r.Start, r.End, r.Step = args[0], args[1], args[2]
+	}
+	if i.isEqual(r.Step, int64(0)) {
+		return nil, &def.RuntimeError{
+			Message: "range() step can't be 0",
+		}
+	}
+	return r, nil
+}
+
+// IntCallable converts a number or a string to an integer
+type IntCallable struct{}
+
+// Arity of the int fn
+func (c *IntCallable) Arity() Arity {
+	return FixedArity(1)
+}
+
+// Call representation of the int fn
+func (c *IntCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	return toInteger(args[0])
+}
+
+// FloatCallable converts a number or a string to a float
+type FloatCallable struct{}
+
+// Arity of the float fn
+func (c *FloatCallable) Arity() Arity {
+	return FixedArity(1)
+}
+
+// Call representation of the float fn
+func (c *FloatCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	return toFloatValue(args[0])
 }
@@ -0,0 +1,36 @@
+package runtime_test
+
+import (
+	"loxlang/parser/def"
+	"loxlang/parser/lexer"
+	"testing"
+)
+
+func TestStringLiteralsInsideInterpolation(t *testing.T) {
+	interpreter := run(t, `
+var a = 1;
+var plain = "${"x"}";
+var joined = "a ${"b" + "1"} c";
+var nested = "outer ${"inner ${a}"}";
+`)
+	expected := map[string]string{
+		"plain":  "x",
+		"joined": "a b1 c",
+		"nested": "outer inner 1",
+	}
+	for name, want := range expected {
+		if got := interpreter.Globals[name]; got != want {
+			t.Errorf("%s is %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestEmptyInterpolation(t *testing.T) {
+	for _, source := range []string{`print "${}";`, `print "a${ }b" + "c";`} {
+		lexer.ScanFile(source, "test.lox")
+		if !def.HadError {
+			t.Errorf("%s was accepted", source)
+		}
+		def.HadError = false
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"loxlang/parser/def"
+	"math/big"
 	"strings"
 )
 
@@ -13,12 +14,39 @@ type Interpreter struct {
 	Env     *Environment
 	Locals  map[def.Expr]int
 	Slots   map[def.Expr]int
+	Loader  ModuleLoader
+	// generator is the one whose body this interpreter runs, if any
+	generator *generator
+	// generators are the ones not finished yet, shared with the interpreters of generator bodies
+	generators *generatorSet
+}
+
+// ModuleLoader loads and runs the modules required by import statements
+type ModuleLoader interface {
+	Load(importer *Interpreter, path string, from def.Token) (*LoxModule, *def.RuntimeError)
+}
+
+var builtins = map[string]interface{}{
+	"clock":  &ClockCallable{},
+	"len":    &LenCallable{},
+	"push":   &PushCallable{},
+	"pop":    &PopCallable{},
+	"keys":   &KeysCallable{},
+	"values": &ValuesCallable{},
+	"has":    &HasCallable{},
+	"delete": &DeleteCallable{},
+	"Error":  &ErrorCallable{},
+	"range":  &RangeCallable{},
+	"int":    &IntCallable{},
+	"float":  &FloatCallable{},
 }
 
 // NewInterpreter creates and sets up new Interpreter
 func NewInterpreter() *Interpreter {
 	globals := map[string]interface{}{}
-	globals["clock"] = &ClockCallable{}
+	for name, value := range builtins {
+		globals[name] = value
+	}
 	return &Interpreter{
 		Globals: globals,
 		Locals:  map[def.Expr]int{},
@@ -26,20 +54,47 @@ func NewInterpreter() *Interpreter {
 	}
 }
 
+// NewModuleInterpreter creates an interpreter with its own globals for a module.
+// Resolved locals are shared with the importer, since module functions may run from its code
+func NewModuleInterpreter(importer *Interpreter) *Interpreter {
+	interpreter := NewInterpreter()
+	interpreter.Locals = importer.Locals
+	interpreter.Slots = importer.Slots
+	interpreter.Loader = importer.Loader
+	interpreter.generators = importer.openGenerators()
+	return interpreter
+}
+
 // Interpret Main method of Interpreter
 func (i *Interpreter) Interpret(stmts []def.Stmt) {
+	err := i.Execute(stmts)
+	if err != nil {
+		def.ReportRuntimeError(err)
+	}
+}
+
+// Execute runs the statements, stopping at the first error
+func (i *Interpreter) Execute(stmts []def.Stmt) *def.RuntimeError {
 	for _, s := range stmts {
 		err := func(stmt def.Stmt) *def.RuntimeError {
 			return i.execute(stmt)
 		}(s)
 		if err != nil {
-			def.ReportRuntimeError(err)
-			break
+			return err
 		}
 	}
+	return nil
+}
+
+// isExported checks if a global is declared by the program and public, names starting with '_' are private
+func isExported(name string, value interface{}) bool {
+	return !strings.HasPrefix(name, "_") && builtins[name] != value
 }
 
 func (i *Interpreter) execute(stmt def.Stmt) *def.RuntimeError {
+	if i.generators != nil {
+		i.generators.closeAbandoned()
+	}
 	err := stmt.Accept(i)
 	return err
 }
@@ -52,20 +107,101 @@ func (i *Interpreter) Resolve(expr def.Expr, scopeDepth, slot int) *def.RuntimeE
 }
 
 func (i *Interpreter) stringfy(value interface{}) string {
+	text, _ := i.toString(def.Token{}, value)
+	return text
+}
+
+// toString is stringfy reporting errors of __str__ at token, the text falls back to the default one
+func (i *Interpreter) toString(token def.Token, value interface{}) (string, *def.RuntimeError) {
+	return i.valueString(token, value, map[interface{}]bool{})
+}
+
+// valueString tracks the containers being printed, one containing itself is shown as [...] or {...}
+func (i *Interpreter) valueString(token def.Token, value interface{}, printing map[interface{}]bool) (string, *def.RuntimeError) {
 	if value == nil {
-		return ""
+		return "", nil
+	}
+	if list, isList := value.(*LoxList); isList {
+		if printing[list] {
+			return "[...]", nil
+		}
+		printing[list] = true
+		defer delete(printing, list)
+		text, err := i.joinStrings(token, list.Elements, printing)
+		return "[" + text + "]", err
+	}
+	if tuple, isTuple := value.(*LoxTuple); isTuple {
+		if printing[tuple] {
+			return "(...)", nil
+		}
+		printing[tuple] = true
+		defer delete(printing, tuple)
+		text, err := i.joinStrings(token, tuple.Elements, printing)
+		return "(" + text + ")", err
+	}
+	if m, isMap := value.(*LoxMap); isMap {
+		if printing[m] {
+			return "{...}", nil
+		}
+		printing[m] = true
+		defer delete(printing, m)
+		var err *def.RuntimeError
+		entries := make([]string, len(m.Keys))
+		for idx, key := range m.Keys {
+			entry, _ := m.Get(key)
+			keyText, keyErr := i.valueString(token, key, printing)
+			entryText, entryErr := i.valueString(token, entry, printing)
+			entries[idx] = keyText + ": " + entryText
+			err = firstError(err, keyErr, entryErr)
+		}
+		return "{" + strings.Join(entries, ", ") + "}", err
+	}
+	if isNumber(value) {
+		return formatNumber(value), nil
+	}
+	if instance, isInstance := value.(*LoxInstance); isInstance {
+		if _, found := instance.Class.FindMethod("__str__"); found {
+			return i.instanceString(token, instance)
+		}
+	}
+
+	return fmt.Sprintf("%v", value), nil
+}
+
+func (i *Interpreter) joinStrings(token def.Token, values []interface{}, printing map[interface{}]bool) (string, *def.RuntimeError) {
+	var err *def.RuntimeError
+	texts := make([]string, len(values))
+	for idx, value := range values {
+		text, textErr := i.valueString(token, value, printing)
+		texts[idx] = text
+		err = firstError(err, textErr)
+	}
+	return strings.Join(texts, ", "), err
+}
+
+// instanceString calls __str__, which must give back a string
+func (i *Interpreter) instanceString(token def.Token, instance *LoxInstance) (string, *def.RuntimeError) {
+	value, err := i.callOperator(instance, "__str__", token)
+	if err != nil {
+		return instance.String(), err
 	}
-	parsed, isFloat := value.(float64)
-	if isFloat {
-		res := fmt.Sprintf("%f", parsed)
-		if strings.HasSuffix(res, ".000000") {
-			text := res[0 : len(res)-7]
-			return text
+	text, isString := value.(string)
+	if !isString {
+		return instance.String(), &def.RuntimeError{
+			Token:   token,
+			Message: "__str__ must return a string",
 		}
-		return res
 	}
+	return text, nil
+}
 
-	return fmt.Sprintf("%v", value)
+func firstError(errs ...*def.RuntimeError) *def.RuntimeError {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // VisitExpressionStmt Handles ExprStmt
@@ -83,7 +219,11 @@ func (i *Interpreter) VisitPrintStmt(print *def.Print) *def.RuntimeError {
 	if err != nil {
 		return err
 	}
-	fmt.Println(i.stringfy(value))
+	text, err := i.toString(print.Keyword, value)
+	if err != nil {
+		return err
+	}
+	fmt.Println(text)
 	return nil
 }
 
@@ -102,6 +242,47 @@ func (i *Interpreter) VisitVar(varStmt *def.Var) *def.RuntimeError {
 	return nil
 }
 
+// VisitDestructure Handles var (x, y) = value;, defining the names in order and the rest last
+func (i *Interpreter) VisitDestructure(destructure *def.Destructure) *def.RuntimeError {
+	value, err := i.evaluate(destructure.Initializer)
+	if err != nil {
+		return err
+	}
+	var elements []interface{}
+	switch collection := value.(type) {
+	case *LoxTuple:
+		elements = collection.Elements
+	case *LoxList:
+		elements = collection.Elements
+	default:
+		return &def.RuntimeError{
+			Token:   destructure.Keyword,
+			Message: "Only tuples and lists can be destructured.",
+		}
+	}
+
+	names := len(destructure.Names)
+	if len(elements) < names || (destructure.Rest == nil && len(elements) != names) {
+		expected := fmt.Sprintf("%d", names)
+		if destructure.Rest != nil {
+			expected = "at least " + expected
+		}
+		return &def.RuntimeError{
+			Token:   destructure.Keyword,
+			Message: fmt.Sprintf("Expected %s values to destructure, but got %d", expected, len(elements)),
+		}
+	}
+	for idx, name := range destructure.Names {
+		i.define(name, elements[idx])
+	}
+	if destructure.Rest != nil {
+		rest := make([]interface{}, len(elements)-names)
+		copy(rest, elements[names:])
+		i.define(*destructure.Rest, &LoxList{Elements: rest})
+	}
+	return nil
+}
+
 // VisitVariableExpr Handles ExprStmt
 func (i *Interpreter) VisitVariableExpr(variable *def.Variable) (interface{}, *def.RuntimeError) {
 	return i.lookupVariable(variable.Name, variable)
@@ -152,23 +333,29 @@ func (i *Interpreter) VisitAssignExpr(assign *def.Assign) (interface{}, *def.Run
 	if err != nil {
 		return nil, err
 	}
+	err = i.assignVariable(assign.Name, assign, value)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
 
-	distance, ok := i.Locals[assign]
-	slot, okSlot := i.Slots[assign]
+func (i *Interpreter) assignVariable(name def.Token, expr def.Expr, value interface{}) *def.RuntimeError {
+	distance, ok := i.Locals[expr]
+	slot, okSlot := i.Slots[expr]
 
 	if ok && okSlot {
 		i.Env.AssignAt(distance, value, slot)
-		return value, nil
+		return nil
 	}
-	if _, ok = i.Globals[assign.Name.Lexeme]; ok {
-		i.Globals[assign.Name.Lexeme] = value
-		return value, nil
+	if _, ok = i.Globals[name.Lexeme]; ok {
+		i.Globals[name.Lexeme] = value
+		return nil
 	}
-	return nil, &def.RuntimeError{
-		Token:   assign.Name,
-		Message: fmt.Sprintf("Undefined variable %s.", assign.Name.Lexeme),
+	return &def.RuntimeError{
+		Token:   name,
+		Message: fmt.Sprintf("Undefined variable %s.", name.Lexeme),
 	}
-
 }
 
 // VisitIf Handles Grouping
@@ -200,53 +387,402 @@ func (i *Interpreter) VisitIf(ifStmt *def.If) *def.RuntimeError {
 
 // VisitWhile Handles Grouping
 func (i *Interpreter) VisitWhile(whileStmt *def.While) *def.RuntimeError {
+	// do-while skips the first check
+	checkCondition := !whileStmt.Do
 	for {
-		condition, err := i.evaluate(whileStmt.Condition)
+		if checkCondition {
+			condition, err := i.evaluate(whileStmt.Condition)
+			if err != nil {
+				return err
+			}
+			result, truthyErr := i.isTruthy(condition)
+			if truthyErr != nil {
+				return &def.RuntimeError{
+					Token:   def.Token{},
+					Message: "Error evaluating isTruthy",
+				}
+			}
+
+			if !result {
+				return nil
+			}
+		}
+		checkCondition = true
+
+		err := i.execute(whileStmt.Body)
 		if err != nil {
-			return err
+			if !ownsControlFlow(err, whileStmt.Label) {
+				return err
+			}
+			if err.Type == def.CONTROLFLOWBREAK {
+				break
+			}
 		}
-		result, truthyErr := i.isTruthy(condition)
-		if truthyErr != nil {
-			return &def.RuntimeError{
-				Token:   def.Token{},
-				Message: "Error evaluating isTruthy",
+
+		if whileStmt.Increment != nil {
+			_, err = i.evaluate(whileStmt.Increment)
+			if err != nil {
+				return err
 			}
 		}
+	}
+	return nil
+}
 
-		if !result {
+// VisitForIn Handles for-in loops, each iteration gets a fresh environment for the loop variable
+func (i *Interpreter) VisitForIn(forIn *def.ForIn) *def.RuntimeError {
+	iterable, err := i.evaluate(forIn.Iterable)
+	if err != nil {
+		return err
+	}
+	iterator, err := i.iteratorFor(forIn.Keyword, iterable)
+	if err != nil {
+		return err
+	}
+	if generator, ok := iterator.(*LoxGenerator); ok {
+		return i.forInGenerator(forIn, generator)
+	}
+	return i.forInLoop(forIn, iterator)
+}
+
+// forInGenerator closes a generator the loop leaves early, by break, return or error
+func (i *Interpreter) forInGenerator(forIn *def.ForIn, generator *LoxGenerator) *def.RuntimeError {
+	err := i.forInLoop(forIn, generator)
+	closeErr := generator.close()
+	if closeErr != nil && closeErr.Token.Line == 0 {
+		closeErr.Token = forIn.Keyword
+	}
+	return firstError(err, closeErr)
+}
+
+func (i *Interpreter) forInLoop(forIn *def.ForIn, iterator Iterator) *def.RuntimeError {
+	for {
+		value, ok, nextErr := iterator.Next(i)
+		if nextErr != nil {
+			if nextErr.Token.Line == 0 {
+				nextErr.Token = forIn.Keyword
+			}
+			return nextErr
+		}
+		if !ok {
 			return nil
 		}
-
-		err = i.execute(whileStmt.Body)
+		env := NewEnvironment(i.Env)
+		env.Define(value)
+		err := i.executeBlock([]def.Stmt{forIn.Body}, env)
 		if err != nil {
-			if err.Type == def.CONTROLFLOWBREAK {
-				break
-			} else {
+			if !ownsControlFlow(err, forIn.Label) {
 				return err
 			}
+			if err.Type == def.CONTROLFLOWBREAK {
+				return nil
+			}
 		}
+	}
+}
 
+// VisitYield Handles Yield, the generator body waits here until the next value is asked
+func (i *Interpreter) VisitYield(yield *def.Yield) *def.RuntimeError {
+	var value interface{}
+	if yield.Value != nil {
+		var err *def.RuntimeError
+		value, err = i.evaluate(yield.Value)
+		if err != nil {
+			return err
+		}
+	}
+	if !i.generator.yield(value) {
+		// the generator was closed, unwind the body running only its finally blocks
+		return &def.RuntimeError{
+			Token: yield.Keyword,
+			Type:  def.GENERATORCLOSED,
+		}
 	}
 	return nil
 }
 
+// ownsControlFlow checks if a loop with label should handle err, a break or continue without label
+// belongs to the innermost loop and one with label goes up to the loop with the same label
+func ownsControlFlow(err *def.RuntimeError, label *def.Token) bool {
+	if err.Type != def.CONTROLFLOWBREAK && err.Type != def.CONTROLFLOWCONTINUE {
+		return false
+	}
+	return err.Label == "" || (label != nil && err.Label == label.Lexeme)
+}
+
 // VisitControlFlow Handles Grouping
 func (i *Interpreter) VisitControlFlow(controlFlow *def.ControlFlow) *def.RuntimeError {
+	err := &def.RuntimeError{
+		Token: controlFlow.Keyword,
+		Type:  controlFlow.Type,
+	}
+	if controlFlow.Label != nil {
+		err.Label = controlFlow.Label.Lexeme
+	}
+	return err
+}
+
+// VisitThrow Handles Throw, the value travels up as a THROWN error until a catch takes it
+func (i *Interpreter) VisitThrow(throw *def.Throw) *def.RuntimeError {
+	value, err := i.evaluate(throw.Value)
+	if err != nil {
+		return err
+	}
+	message := i.stringfy(value)
+	if instance, ok := value.(*LoxInstance); ok && instance.Class == ErrorClass {
+		if instance.Fields["line"] == nil {
+			instance.Fields["line"] = int64(throw.Keyword.Line)
+		}
+		message = i.stringfy(instance.Fields["message"])
+	}
 	return &def.RuntimeError{
-		Type: controlFlow.Type,
+		Token:   throw.Keyword,
+		Message: "Uncaught exception: " + message,
+		Type:    def.THROWN,
+		Value:   value,
+	}
+}
+
+// VisitTry Handles try/catch/finally
+func (i *Interpreter) VisitTry(try *def.Try) *def.RuntimeError {
+	err := i.executeBlock(try.Body, NewEnvironment(i.Env))
+
+	// break, continue and return aren't exceptions, they only go through finally
+	if err != nil && try.CatchBody != nil && (err.Type == def.NORMAL || err.Type == def.THROWN) {
+		exception := err.Value
+		if err.Type == def.NORMAL {
+			exception = NewErrorInstance(err.Message, int64(err.Token.Line))
+		}
+		catchEnv := NewEnvironment(i.Env)
+		catchEnv.Define(exception)
+		err = i.executeBlock(try.CatchBody, catchEnv)
+	}
+
+	if try.FinallyBody != nil {
+		finallyErr := i.executeBlock(try.FinallyBody, NewEnvironment(i.Env))
+		if finallyErr != nil {
+			return finallyErr
+		}
+	}
+	return err
+}
+
+// VisitImport Handles Import
+func (i *Interpreter) VisitImport(importStmt *def.Import) *def.RuntimeError {
+	if i.Loader == nil {
+		return &def.RuntimeError{
+			Token:   importStmt.Keyword,
+			Message: "Modules can't be imported here.",
+		}
+	}
+	module, err := i.Loader.Load(i, importStmt.Path.Literal.(string), importStmt.Keyword)
+	if err != nil {
+		return err
 	}
+	if len(importStmt.Names) == 0 {
+		i.define(importStmt.Alias, module)
+		return nil
+	}
+	for _, name := range importStmt.Names {
+		value, err := module.Get(name)
+		if err != nil {
+			return err
+		}
+		i.define(name, value)
+	}
+	return nil
+}
+
+// VisitMatch Handles Match, runs the first case whose pattern matches and whose guard holds
+func (i *Interpreter) VisitMatch(match *def.Match) *def.RuntimeError {
+	subject, err := i.evaluate(match.Subject)
+	if err != nil {
+		return err
+	}
+	for _, matchCase := range match.Cases {
+		for _, pattern := range matchCase.Patterns {
+			caseEnv := NewEnvironment(i.Env)
+			matches, matchErr := i.matchPattern(pattern, subject, caseEnv)
+			if matchErr != nil {
+				return matchErr
+			}
+			if !matches {
+				continue
+			}
+			if matchCase.Guard != nil {
+				holds, guardErr := i.evaluateIn(matchCase.Guard, caseEnv)
+				if guardErr != nil {
+					return guardErr
+				}
+				result, truthyErr := i.isTruthy(holds)
+				if truthyErr != nil {
+					return &def.RuntimeError{
+						Token:   matchCase.Keyword,
+						Message: "Error evaluating isTruthy",
+					}
+				}
+				if !result {
+					continue
+				}
+			}
+			return i.executeBlock([]def.Stmt{matchCase.Body}, caseEnv)
+		}
+	}
+	return nil
+}
+
+// matchPattern checks value against pattern, defining the bound names in env
+func (i *Interpreter) matchPattern(pattern *def.Pattern, value interface{}, env *Environment) (bool, *def.RuntimeError) {
+	switch pattern.Kind {
+	case def.PATTERNWILDCARD:
+		return true, nil
+	case def.PATTERNBIND:
+		env.Define(value)
+		return true, nil
+	case def.PATTERNLITERAL:
+		return i.isEqual(value, pattern.Value), nil
+	case def.PATTERNMEMBER:
+		member, err := i.evaluateIn(pattern.Member, env)
+		if err != nil {
+			return false, err
+		}
+		return i.isEqual(value, member), nil
+	case def.PATTERNRANGE:
+		if isNumber(value) {
+			if !isNumber(pattern.Value) || !isNumber(pattern.High) {
+				return false, nil
+			}
+			low, lowOk := compareNumbers(value, pattern.Value)
+			high, highOk := compareNumbers(value, pattern.High)
+			return lowOk && highOk && low >= 0 && high <= 0, nil
+		}
+		if text, ok := value.(string); ok {
+			low, lowOk := pattern.Value.(string)
+			high, highOk := pattern.High.(string)
+			return lowOk && highOk && text >= low && text <= high, nil
+		}
+		return false, nil
+	case def.PATTERNLIST:
+		// tuples match list patterns too, the rest is a list like in destructuring
+		var elements []interface{}
+		switch collection := value.(type) {
+		case *LoxList:
+			elements = collection.Elements
+		case *LoxTuple:
+			elements = collection.Elements
+		default:
+			return false, nil
+		}
+		if len(elements) < len(pattern.Elements) {
+			return false, nil
+		}
+		if pattern.Rest == nil && len(elements) != len(pattern.Elements) {
+			return false, nil
+		}
+		for idx, element := range pattern.Elements {
+			matches, err := i.matchPattern(element, elements[idx], env)
+			if err != nil || !matches {
+				return false, err
+			}
+		}
+		if pattern.Rest != nil {
+			rest := make([]interface{}, len(elements)-len(pattern.Elements))
+			copy(rest, elements[len(pattern.Elements):])
+			env.Define(&LoxList{Elements: rest})
+		}
+		return true, nil
+	case def.PATTERNMAP:
+		m, ok := value.(*LoxMap)
+		if !ok {
+			return false, nil
+		}
+		for idx, key := range pattern.Keys {
+			entry, found := m.Get(key)
+			if !found {
+				return false, nil
+			}
+			matches, err := i.matchPattern(pattern.Elements[idx], entry, env)
+			if err != nil || !matches {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// evaluateIn evaluates expr inside env, going back to the current environment after
+func (i *Interpreter) evaluateIn(expr def.Expr, env *Environment) (interface{}, *def.RuntimeError) {
+	previous := i.Env
+	defer func() { i.Env = previous }()
+	i.Env = env
+	return i.evaluate(expr)
 }
 
 // VisitFunction Handles Function
 func (i *Interpreter) VisitFunction(function *def.Function) *def.RuntimeError {
-	callable := CallableFunction{Name: function.Name.Lexeme, FunctionExpr: function.FuncExpr, Closure: i.Env}
+	callable := &CallableFunction{Name: function.Name.Lexeme, FunctionExpr: function.FuncExpr, Closure: i.Env, Globals: i.Globals}
 	i.define(function.Name, callable)
 	return nil
 }
 
+// VisitEnum Handles Enum declarations, each member is created once here
+func (i *Interpreter) VisitEnum(enum *def.Enum) *def.RuntimeError {
+	loxEnum := &LoxEnum{Name: enum.Name.Lexeme}
+	for idx, member := range enum.Members {
+		loxEnum.Members = append(loxEnum.Members, &LoxEnumMember{
+			Enum:    loxEnum,
+			Name:    member.Lexeme,
+			Ordinal: int64(idx),
+		})
+	}
+	i.define(enum.Name, loxEnum)
+	return nil
+}
+
+// VisitClass Handles Class declarations
+func (i *Interpreter) VisitClass(class *def.Class) *def.RuntimeError {
+	var superclass *LoxClass
+	if class.Superclass != nil {
+		value, err := i.evaluate(class.Superclass)
+		if err != nil {
+			return err
+		}
+		parent, ok := value.(*LoxClass)
+		if !ok {
+			return &def.RuntimeError{
+				Token:   class.Superclass.Name,
+				Message: "Superclass must be a class.",
+			}
+		}
+		superclass = parent
+	}
+
+	enclosing := i.Env
+	if superclass != nil {
+		// 'super' lives in its own environment, between the class and its methods
+		i.Env = NewEnvironment(i.Env)
+		i.Env.Define(superclass)
+	}
+
+	methods := map[string]*CallableFunction{}
+	for _, method := range class.Methods {
+		methods[method.Name.Lexeme] = &CallableFunction{
+			Name:          method.Name.Lexeme,
+			FunctionExpr:  method.FuncExpr,
+			Closure:       i.Env,
+			Globals:       i.Globals,
+			IsInitializer: method.Name.Lexeme == "init",
+		}
+	}
+	i.Env = enclosing
+	i.define(class.Name, &LoxClass{Name: class.Name.Lexeme, Superclass: superclass, Methods: methods})
+	return nil
+}
+
 // VisitFunctionExpr Handles anonymous functions
 func (i *Interpreter) VisitFunctionExpr(function *def.FunctionExpr) (interface{}, *def.RuntimeError) {
-	return CallableFunction{Name: "", FunctionExpr: *function, Closure: i.Env}, nil
+	return &CallableFunction{Name: "", FunctionExpr: *function, Closure: i.Env, Globals: i.Globals}, nil
 }
 
 // VisitReturnStmt Handles Return inside function
@@ -277,9 +813,6 @@ func (i *Interpreter) VisitGroupingExpr(grouping *def.Grouping) (interface{}, *d
 
 // VisitBinaryExpr Handles Binary
 func (i *Interpreter) VisitBinaryExpr(binary *def.Binary) (interface{}, *def.RuntimeError) {
-	var ok *def.RuntimeError
-	var leftVal, rightVal float64
-
 	right, rOk := i.evaluate(binary.Right)
 	if rOk != nil {
 		return nil, rOk
@@ -288,72 +821,52 @@ func (i *Interpreter) VisitBinaryExpr(binary *def.Binary) (interface{}, *def.Run
 	if lOk != nil {
 		return nil, lOk
 	}
+	return i.binaryOperation(binary.Token, left, right)
+}
 
-	switch binary.Token.Type {
-	case def.GREATER:
-		leftVal, rightVal, ok = i.checkNumberOperands(binary.Token, left, right)
-		if ok != nil {
-			return nil, ok
-		}
-		return leftVal > rightVal, nil
-	case def.GREATEREQUAL:
-		leftVal, rightVal, ok = i.checkNumberOperands(binary.Token, left, right)
-
+func (i *Interpreter) binaryOperation(operator def.Token, left interface{}, right interface{}) (interface{}, *def.RuntimeError) {
+	if value, handled, err := i.overloadedBinary(operator, left, right); handled {
+		return value, err
+	}
+	switch operator.Type {
+	case def.GREATER, def.GREATEREQUAL, def.LESS, def.LESSEQUAL:
+		ok := i.checkNumberOperands(operator, left, right)
 		if ok != nil {
 			return nil, ok
 		}
-		return leftVal >= rightVal, nil
-	case def.LESS:
-		leftVal, rightVal, ok = i.checkNumberOperands(binary.Token, left, right)
-
-		if ok != nil {
-			return nil, ok
+		comparison, comparable := compareNumbers(left, right)
+		if !comparable {
+			// NaN is never ordered
+			return false, nil
 		}
-		return leftVal < rightVal, nil
-	case def.LESSEQUAL:
-		leftVal, rightVal, ok = i.checkNumberOperands(binary.Token, left, right)
-
-		if ok != nil {
-			return nil, ok
+		switch operator.Type {
+		case def.GREATER:
+			return comparison > 0, nil
+		case def.GREATEREQUAL:
+			return comparison >= 0, nil
+		case def.LESS:
+			return comparison < 0, nil
 		}
-		return leftVal <= rightVal, nil
+		return comparison <= 0, nil
 	case def.BANGEQUAL:
 		return !i.isEqual(left, right), nil
 	case def.EQUALEQUAL:
 		return i.isEqual(left, right), nil
-	case def.MINUS:
-		leftVal, rightVal, ok = i.checkNumberOperands(binary.Token, left, right)
-
-		if ok != nil {
-			return nil, ok
-		}
-		return leftVal - rightVal, nil
-	case def.SLASH:
-		leftVal, rightVal, ok = i.checkNumberOperands(binary.Token, left, right)
-
+	case def.MINUS, def.SLASH, def.STAR, def.PERCENT, def.TILDESLASH, def.STARSTAR:
+		ok := i.checkNumberOperands(operator, left, right)
 		if ok != nil {
 			return nil, ok
 		}
-		if rightVal == 0.0 {
-			divideByZeroError := &def.RuntimeError{
-				Token:   binary.Token,
-				Message: "Can't divide by 0",
-			}
-			return nil, divideByZeroError
+		return arithmetic(operator, left, right)
+	case def.AMPERSAND, def.PIPE, def.CARET, def.LESSLESS, def.GREATERGREATER:
+		leftInt, rightInt, intOk := i.checkIntegerOperands(operator, left, right)
+		if intOk != nil {
+			return nil, intOk
 		}
-		return leftVal / rightVal, nil
-	case def.STAR:
-		leftVal, rightVal, ok = i.checkNumberOperands(binary.Token, left, right)
-
-		if ok != nil {
-			return nil, ok
-		}
-		return leftVal * rightVal, nil
+		return i.bitwise(operator, leftInt, rightInt)
 	case def.PLUS:
-		floatLeft, isFloatLeft := left.(float64)
-		floatRight, isFloatRight := right.(float64)
-		if isFloatLeft && isFloatRight {
-			return floatLeft + floatRight, nil
+		if isNumber(left) && isNumber(right) {
+			return arithmetic(operator, left, right)
 		}
 
 		stringRight, isStringRight := right.(string)
@@ -362,7 +875,7 @@ func (i *Interpreter) VisitBinaryExpr(binary *def.Binary) (interface{}, *def.Run
 			return stringLeft + stringRight, nil
 		}
 		return nil, &def.RuntimeError{
-			Token:   binary.Token,
+			Token:   operator,
 			Message: "Invalid values in + operator",
 		}
 	}
@@ -375,6 +888,12 @@ func (i *Interpreter) VisitLogicalExpr(logical *def.Logical) (interface{}, *def.
 	if err != nil {
 		return nil, err
 	}
+	if logical.Operator.Type == def.QUESTIONQUESTION {
+		if left != nil {
+			return left, nil
+		}
+		return i.evaluate(logical.Right)
+	}
 	result, tErr := i.isTruthy(left)
 	if tErr != nil {
 		return nil, &def.RuntimeError{
@@ -416,11 +935,20 @@ func (i *Interpreter) VisitUnaryExpr(unary *def.Unary) (interface{}, *def.Runtim
 		}
 		return !res, nil
 	case def.MINUS:
-		value, mOk := i.checkNumberOperand(unary.Token, right)
+		if value, handled, err := i.overloadedUnary(unary.Token, right); handled {
+			return value, err
+		}
+		mOk := i.checkNumberOperand(unary.Token, right)
 		if mOk != nil {
 			return nil, mOk
 		}
-		return -value, nil
+		return negate(right), nil
+	case def.TILDE:
+		value, tOk := i.checkIntegerOperand(unary.Token, right)
+		if tOk != nil {
+			return nil, tOk
+		}
+		return normalize(new(big.Int).Not(value)), nil
 	}
 	return nil, nil
 }
@@ -442,7 +970,7 @@ func (i *Interpreter) VisitCallExpr(call *def.Call) (interface{}, *def.RuntimeEr
 		}
 		args = append(args, arg)
 	}
-	callable, ok := callee.(CallableFunction)
+	callable, ok := callee.(Callable)
 	if !ok {
 		return nil, &def.RuntimeError{
 			Token:   call.Paren,
@@ -450,13 +978,528 @@ func (i *Interpreter) VisitCallExpr(call *def.Call) (interface{}, *def.RuntimeEr
 		}
 	}
 
-	if len(args) != callable.Arity() {
+	arity := callable.Arity()
+	if arity.Max != Variadic && len(args) > arity.Max {
 		return nil, &def.RuntimeError{
 			Token:   call.Paren,
-			Message: fmt.Sprintf("Expeted %d argumentos, but got %d", callable.Arity(), len(args)),
+			Message: fmt.Sprintf("Expected %s arguments, but got %d", arity, len(args)),
+		}
+	}
+	if len(call.NamedArguments) > 0 {
+		args, err = i.bindNamedArguments(call, callable, args)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(args) < arity.Min {
+		return nil, &def.RuntimeError{
+			Token:   call.Paren,
+			Message: fmt.Sprintf("Expected %s arguments, but got %d", arity, len(args)),
+		}
+	}
+	value, err := callable.Call(i, args)
+	if err != nil && err.Token.Line == 0 {
+		// native functions don't know where they were called from
+		err.Token = call.Paren
+	}
+	return value, err
+}
+
+// bindNamedArguments puts the named arguments in the place of their params, after the positional ones
+func (i *Interpreter) bindNamedArguments(call *def.Call, callable Callable, args []interface{}) ([]interface{}, *def.RuntimeError) {
+	named, ok := callable.(NamedCallable)
+	if !ok || len(named.ParamNames()) == 0 {
+		return nil, &def.RuntimeError{
+			Token:   call.NamedArguments[0].Name,
+			Message: "This function doesn't accept named arguments",
+		}
+	}
+	names := named.ParamNames()
+	if len(args) > len(names) {
+		return nil, &def.RuntimeError{
+			Token:   call.NamedArguments[0].Name,
+			Message: "Named arguments can't be used with variadic arguments",
+		}
+	}
+	bound := make([]interface{}, len(names))
+	for idx := range bound {
+		bound[idx] = Missing
+	}
+	copy(bound, args)
+	last := len(args) - 1
+
+	for _, argument := range call.NamedArguments {
+		position := -1
+		for idx, name := range names {
+			if name == argument.Name.Lexeme {
+				position = idx
+			}
+		}
+		if position == -1 {
+			return nil, &def.RuntimeError{
+				Token:   argument.Name,
+				Message: fmt.Sprintf("Unknown parameter '%s'", argument.Name.Lexeme),
+			}
+		}
+		if bound[position] != Missing {
+			return nil, &def.RuntimeError{
+				Token:   argument.Name,
+				Message: fmt.Sprintf("Argument '%s' given more than once", argument.Name.Lexeme),
+			}
+		}
+		value, err := i.evaluate(argument.Value)
+		if err != nil {
+			return nil, err
+		}
+		bound[position] = value
+		if position > last {
+			last = position
+		}
+	}
+
+	arity := callable.Arity()
+	for idx := 0; idx < arity.Min; idx++ {
+		if bound[idx] == Missing {
+			return nil, &def.RuntimeError{
+				Token:   call.Paren,
+				Message: fmt.Sprintf("Missing argument '%s'", names[idx]),
+			}
+		}
+	}
+	return bound[:last+1], nil
+}
+
+// VisitConditionalExpr Handles the ternary operator, only the chosen branch is evaluated
+func (i *Interpreter) VisitConditionalExpr(conditional *def.Conditional) (interface{}, *def.RuntimeError) {
+	condition, err := i.evaluate(conditional.Condition)
+	if err != nil {
+		return nil, err
+	}
+	result, truthyErr := i.isTruthy(condition)
+	if truthyErr != nil {
+		return nil, &def.RuntimeError{
+			Token:   conditional.Question,
+			Message: "Invalid isTruthy boolean parse",
+		}
+	}
+	if result {
+		return i.evaluate(conditional.Then)
+	}
+	return i.evaluate(conditional.Else)
+}
+
+// VisitOptionalChainExpr Handles chains with a ?., they are nil when one of them found nil
+func (i *Interpreter) VisitOptionalChainExpr(chain *def.OptionalChain) (interface{}, *def.RuntimeError) {
+	value, err := i.evaluate(chain.Expr)
+	if err != nil && err.Type == def.OPTIONALNIL {
+		return nil, nil
+	}
+	return value, err
+}
+
+// VisitGetExpr Handles property access
+func (i *Interpreter) VisitGetExpr(get *def.Get) (interface{}, *def.RuntimeError) {
+	object, err := i.evaluate(get.Object)
+	if err != nil {
+		return nil, err
+	}
+	if object == nil && get.Optional {
+		// skips the rest of the chain, VisitOptionalChainExpr gives back the nil
+		return nil, &def.RuntimeError{Token: get.Name, Type: def.OPTIONALNIL}
+	}
+	if instance, ok := object.(*LoxInstance); ok {
+		return instance.Get(get.Name)
+	}
+	if module, ok := object.(*LoxModule); ok {
+		return module.Get(get.Name)
+	}
+	if generator, ok := object.(*LoxGenerator); ok {
+		return generator.Get(get.Name)
+	}
+	if enum, ok := object.(*LoxEnum); ok {
+		return enum.Get(get.Name)
+	}
+	if member, ok := object.(*LoxEnumMember); ok {
+		return member.Get(get.Name)
+	}
+	return nil, &def.RuntimeError{
+		Token:   get.Name,
+		Message: "Only instances have properties.",
+	}
+}
+
+// VisitSetExpr Handles property assign
+func (i *Interpreter) VisitSetExpr(set *def.Set) (interface{}, *def.RuntimeError) {
+	object, err := i.evaluate(set.Object)
+	if err != nil {
+		return nil, err
+	}
+	instance, ok := object.(*LoxInstance)
+	if !ok {
+		return nil, fieldAssignError(set.Name, object)
+	}
+	value, err := i.evaluate(set.Value)
+	if err != nil {
+		return nil, err
+	}
+	instance.Set(set.Name, value)
+	return value, nil
+}
+
+// VisitThisExpr Handles 'this' inside methods
+func (i *Interpreter) VisitThisExpr(this *def.This) (interface{}, *def.RuntimeError) {
+	return i.lookupVariable(this.Keyword, this)
+}
+
+// VisitSuperExpr Handles 'super' method access
+func (i *Interpreter) VisitSuperExpr(super *def.Super) (interface{}, *def.RuntimeError) {
+	distance := i.Locals[super]
+	value, err := i.Env.GetAt(distance, i.Slots[super])
+	if err != nil {
+		return nil, err
+	}
+	superclass := value.(*LoxClass)
+	// 'this' is always bound in the environment right inside the one holding 'super'
+	object, err := i.Env.GetAt(distance-1, 0)
+	if err != nil {
+		return nil, err
+	}
+	method, ok := superclass.FindMethod(super.Method.Lexeme)
+	if !ok {
+		return nil, &def.RuntimeError{
+			Token:   super.Method,
+			Message: fmt.Sprintf("Undefined property %s.", super.Method.Lexeme),
+		}
+	}
+	return method.Bind(object.(*LoxInstance)), nil
+}
+
+// VisitListLiteralExpr Handles list literals
+func (i *Interpreter) VisitListLiteralExpr(list *def.ListLiteral) (interface{}, *def.RuntimeError) {
+	elements := []interface{}{}
+	for _, element := range list.Elements {
+		value, err := i.evaluate(element)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, value)
+	}
+	return &LoxList{Elements: elements}, nil
+}
+
+// VisitTupleExpr Handles tuples, like the values of return a, b;
+func (i *Interpreter) VisitTupleExpr(tuple *def.Tuple) (interface{}, *def.RuntimeError) {
+	elements := []interface{}{}
+	for _, element := range tuple.Elements {
+		value, err := i.evaluate(element)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, value)
+	}
+	return &LoxTuple{Elements: elements}, nil
+}
+
+// VisitMapLiteralExpr Handles map literals
+func (i *Interpreter) VisitMapLiteralExpr(mapLiteral *def.MapLiteral) (interface{}, *def.RuntimeError) {
+	m := NewLoxMap()
+	for idx := range mapLiteral.Keys {
+		key, err := i.evaluate(mapLiteral.Keys[idx])
+		if err != nil {
+			return nil, err
+		}
+		err = i.checkMapKey(mapLiteral.Brace, key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := i.evaluate(mapLiteral.Values[idx])
+		if err != nil {
+			return nil, err
+		}
+		m.Set(key, value)
+	}
+	return m, nil
+}
+
+// VisitIndexExpr Handles index access on lists and maps
+func (i *Interpreter) VisitIndexExpr(index *def.Index) (interface{}, *def.RuntimeError) {
+	object, err := i.evaluate(index.Object)
+	if err != nil {
+		return nil, err
+	}
+	switch collection := object.(type) {
+	case *LoxList:
+		key, err := i.evaluate(index.Index)
+		if err != nil {
+			return nil, err
+		}
+		position, err := i.listIndex(index.Bracket, collection.Elements, key)
+		if err != nil {
+			return nil, err
+		}
+		return collection.Elements[position], nil
+	case *LoxTuple:
+		key, err := i.evaluate(index.Index)
+		if err != nil {
+			return nil, err
+		}
+		position, err := i.listIndex(index.Bracket, collection.Elements, key)
+		if err != nil {
+			return nil, err
+		}
+		return collection.Elements[position], nil
+	case *LoxMap:
+		key, err := i.evaluate(index.Index)
+		if err != nil {
+			return nil, err
+		}
+		err = i.checkMapKey(index.Bracket, key)
+		if err != nil {
+			return nil, err
+		}
+		// reading a missing key is an error, has() checks it beforehand
+		value, ok := collection.Get(key)
+		if !ok {
+			return nil, &def.RuntimeError{
+				Token:   index.Bracket,
+				Message: fmt.Sprintf("Undefined key %s.", i.stringfy(key)),
+			}
+		}
+		return value, nil
+	}
+	return nil, &def.RuntimeError{
+		Token:   index.Bracket,
+		Message: "Only lists, tuples and maps can be indexed.",
+	}
+}
+
+// VisitIndexSetExpr Handles index assign on lists and maps
+func (i *Interpreter) VisitIndexSetExpr(indexSet *def.IndexSet) (interface{}, *def.RuntimeError) {
+	object, err := i.evaluate(indexSet.Object)
+	if err != nil {
+		return nil, err
+	}
+	switch collection := object.(type) {
+	case *LoxList:
+		key, err := i.evaluate(indexSet.Index)
+		if err != nil {
+			return nil, err
+		}
+		position, err := i.listIndex(indexSet.Bracket, collection.Elements, key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := i.evaluate(indexSet.Value)
+		if err != nil {
+			return nil, err
+		}
+		collection.Elements[position] = value
+		return value, nil
+	case *LoxMap:
+		key, err := i.evaluate(indexSet.Index)
+		if err != nil {
+			return nil, err
+		}
+		err = i.checkMapKey(indexSet.Bracket, key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := i.evaluate(indexSet.Value)
+		if err != nil {
+			return nil, err
+		}
+		collection.Set(key, value)
+		return value, nil
+	}
+	return nil, indexAssignError(indexSet.Bracket, object)
+}
+
+// fieldAssignError explains why a value can't be assigned to a property, modules are read-only
+func fieldAssignError(name def.Token, object interface{}) *def.RuntimeError {
+	if _, isModule := object.(*LoxModule); isModule {
+		return &def.RuntimeError{
+			Token:   name,
+			Message: "Module members are read-only.",
+		}
+	}
+	return &def.RuntimeError{
+		Token:   name,
+		Message: "Only instances have fields.",
+	}
+}
+
+// indexAssignError explains why a value can't be assigned by index, tuples can only be read
+func indexAssignError(bracket def.Token, object interface{}) *def.RuntimeError {
+	if _, isTuple := object.(*LoxTuple); isTuple {
+		return &def.RuntimeError{
+			Token:   bracket,
+			Message: "Tuples are immutable.",
+		}
+	}
+	return &def.RuntimeError{
+		Token:   bracket,
+		Message: "Only lists and maps can be indexed.",
+	}
+}
+
+// VisitInterpolationExpr Handles string interpolation, each part is shown like print does
+func (i *Interpreter) VisitInterpolationExpr(interpolation *def.Interpolation) (interface{}, *def.RuntimeError) {
+	var builder strings.Builder
+	for _, part := range interpolation.Parts {
+		value, err := i.evaluate(part)
+		if err != nil {
+			return nil, err
+		}
+		text, err := i.toString(interpolation.Token, value)
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(text)
+	}
+	return builder.String(), nil
+}
+
+// VisitCompoundAssignExpr Handles compound assigns, like +=
+func (i *Interpreter) VisitCompoundAssignExpr(compound *def.CompoundAssign) (interface{}, *def.RuntimeError) {
+	_, value, err := i.updateTarget(compound, compound.Target, func(old interface{}) (interface{}, *def.RuntimeError) {
+		right, err := i.evaluate(compound.Value)
+		if err != nil {
+			return nil, err
 		}
+		return i.binaryOperation(compound.Operator, old, right)
+	})
+	return value, err
+}
+
+// VisitIncrementExpr Handles ++ and --, prefix gives the updated value and postfix the previous one
+func (i *Interpreter) VisitIncrementExpr(increment *def.Increment) (interface{}, *def.RuntimeError) {
+	old, value, err := i.updateTarget(increment, increment.Target, func(old interface{}) (interface{}, *def.RuntimeError) {
+		if err := i.checkNumberOperand(increment.Operator, old); err != nil {
+			return nil, err
+		}
+		return i.binaryOperation(increment.Operator, old, int64(1))
+	})
+	if increment.Prefix {
+		return value, err
 	}
-	return callable.Call(i, args)
+	return old, err
+}
+
+// updateTarget reads the target of an update only once, applies update to it and stores the result back
+func (i *Interpreter) updateTarget(node def.Expr, target def.Expr, update func(interface{}) (interface{}, *def.RuntimeError)) (interface{}, interface{}, *def.RuntimeError) {
+	switch t := target.(type) {
+	case *def.Variable:
+		old, err := i.lookupVariable(t.Name, node)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := update(old)
+		if err != nil {
+			return nil, nil, err
+		}
+		return old, value, i.assignVariable(t.Name, node, value)
+	case *def.Get:
+		object, err := i.evaluate(t.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+		instance, ok := object.(*LoxInstance)
+		if !ok {
+			return nil, nil, fieldAssignError(t.Name, object)
+		}
+		old, err := instance.Get(t.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := update(old)
+		if err != nil {
+			return nil, nil, err
+		}
+		instance.Set(t.Name, value)
+		return old, value, nil
+	case *def.Index:
+		object, err := i.evaluate(t.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, err := i.evaluate(t.Index)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch collection := object.(type) {
+		case *LoxList:
+			position, err := i.listIndex(t.Bracket, collection.Elements, key)
+			if err != nil {
+				return nil, nil, err
+			}
+			old := collection.Elements[position]
+			value, err := update(old)
+			if err != nil {
+				return nil, nil, err
+			}
+			collection.Elements[position] = value
+			return old, value, nil
+		case *LoxMap:
+			err = i.checkMapKey(t.Bracket, key)
+			if err != nil {
+				return nil, nil, err
+			}
+			old, ok := collection.Get(key)
+			if !ok {
+				return nil, nil, &def.RuntimeError{
+					Token:   t.Bracket,
+					Message: fmt.Sprintf("Undefined key %s.", i.stringfy(key)),
+				}
+			}
+			value, err := update(old)
+			if err != nil {
+				return nil, nil, err
+			}
+			collection.Set(key, value)
+			return old, value, nil
+		}
+		return nil, nil, indexAssignError(t.Bracket, object)
+	}
+	return nil, nil, nil
+}
+
+func (i *Interpreter) checkMapKey(token def.Token, key interface{}) *def.RuntimeError {
+	switch key.(type) {
+	case string, int64, *big.Int, float64, *LoxEnumMember:
+		return nil
+	}
+	return &def.RuntimeError{
+		Token:   token,
+		Message: "Map keys must be strings, numbers or enum members.",
+	}
+}
+
+func (i *Interpreter) listIndex(bracket def.Token, elements []interface{}, value interface{}) (int, *def.RuntimeError) {
+	if _, isBig := value.(*big.Int); isBig {
+		return 0, &def.RuntimeError{
+			Token:   bracket,
+			Message: fmt.Sprintf("List index %s out of range.", formatNumber(value)),
+		}
+	}
+	index, ok := integerValue(value)
+	if !ok {
+		return 0, &def.RuntimeError{
+			Token:   bracket,
+			Message: "List index must be an integer.",
+		}
+	}
+	if index < 0 {
+		return 0, &def.RuntimeError{
+			Token:   bracket,
+			Message: fmt.Sprintf("Negative list index %d.", int(index)),
+		}
+	}
+	if int(index) >= len(elements) {
+		return 0, &def.RuntimeError{
+			Token:   bracket,
+			Message: fmt.Sprintf("List index %d out of range.", int(index)),
+		}
+	}
+	return int(index), nil
 }
 
 func (i Interpreter) isTruthy(value interface{}) (bool, error) {
@@ -476,33 +1519,99 @@ func (i Interpreter) isEqual(a interface{}, b interface{}) bool {
 	if a == nil {
 		return false
 	}
+	if isNumber(a) && isNumber(b) {
+		// 1 == 1.0, and big integers compare by value
+		comparison, comparable := compareNumbers(a, b)
+		return comparable && comparison == 0
+	}
+	// tuples are values, equal when their elements are
+	leftTuple, leftIsTuple := a.(*LoxTuple)
+	rightTuple, rightIsTuple := b.(*LoxTuple)
+	if leftIsTuple && rightIsTuple {
+		if len(leftTuple.Elements) != len(rightTuple.Elements) {
+			return false
+		}
+		for idx := range leftTuple.Elements {
+			if !i.isEqual(leftTuple.Elements[idx], rightTuple.Elements[idx]) {
+				return false
+			}
+		}
+		return true
+	}
 	return a == b
 }
 
-func (i Interpreter) checkNumberOperand(token def.Token, operand interface{}) (float64, *def.RuntimeError) {
-	value, ok := operand.(float64)
-	if !ok {
-		return 0, &def.RuntimeError{
+func (i Interpreter) checkNumberOperand(token def.Token, operand interface{}) *def.RuntimeError {
+	if !isNumber(operand) {
+		return &def.RuntimeError{
+			Token:   token,
+			Message: "Operand must be a number",
+		}
+	}
+	return nil
+}
+
+func (i Interpreter) checkNumberOperands(token def.Token, left interface{}, right interface{}) *def.RuntimeError {
+	if !isNumber(left) || !isNumber(right) {
+		return &def.RuntimeError{
 			Token:   token,
 			Message: "Operand must be a number",
 		}
 	}
+	return nil
+}
+
+func (i Interpreter) checkIntegerOperand(token def.Token, operand interface{}) (*big.Int, *def.RuntimeError) {
+	value, ok := bitOperand(operand)
+	if !ok {
+		return nil, &def.RuntimeError{
+			Token:   token,
+			Message: "Operand must be an integer",
+		}
+	}
 	return value, nil
 }
 
-func (i Interpreter) checkNumberOperands(token def.Token, left interface{}, right interface{}) (float64, float64, *def.RuntimeError) {
-	leftVal, lOk := left.(float64)
-	rightVal, rOk := right.(float64)
+func (i Interpreter) checkIntegerOperands(token def.Token, left interface{}, right interface{}) (*big.Int, *big.Int, *def.RuntimeError) {
+	leftVal, lOk := bitOperand(left)
+	rightVal, rOk := bitOperand(right)
 
 	if !lOk || !rOk {
-		return 0, 0, &def.RuntimeError{
+		return nil, nil, &def.RuntimeError{
 			Token:   token,
-			Message: "Operand must be a number",
+			Message: "Operands must be integers",
 		}
 	}
 	return leftVal, rightVal, nil
 }
 
+func (i Interpreter) bitwise(operator def.Token, left *big.Int, right *big.Int) (interface{}, *def.RuntimeError) {
+	switch operator.Type {
+	case def.AMPERSAND:
+		return normalize(new(big.Int).And(left, right)), nil
+	case def.PIPE:
+		return normalize(new(big.Int).Or(left, right)), nil
+	case def.CARET:
+		return normalize(new(big.Int).Xor(left, right)), nil
+	}
+	if right.Sign() < 0 {
+		return nil, &def.RuntimeError{
+			Token:   operator,
+			Message: "Shift count can't be negative",
+		}
+	}
+	if !right.IsInt64() || right.Int64() > 1<<20 {
+		return nil, &def.RuntimeError{
+			Token:   operator,
+			Message: "Shift count is too big",
+		}
+	}
+	if operator.Type == def.LESSLESS {
+		return normalize(new(big.Int).Lsh(left, uint(right.Int64()))), nil
+	}
+	return normalize(new(big.Int).Rsh(left, uint(right.Int64()))), nil
+}
+
 func (i *Interpreter) evaluate(expr def.Expr) (interface{}, *def.RuntimeError) {
 	return expr.Accept(i)
 }
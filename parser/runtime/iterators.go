@@ -0,0 +1,140 @@
+package runtime
+
+import (
+	"fmt"
+	"loxlang/parser/def"
+)
+
+// Iterator walks the values of a for-in loop, ok is false when there are no more values
+type Iterator interface {
+	Next(i *Interpreter) (value interface{}, ok bool, err *def.RuntimeError)
+}
+
+// LoxRange is the lazy sequence of numbers created by range()
+type LoxRange struct {
+	Start interface{}
+	End   interface{}
+	Step  interface{}
+}
+
+// String shows the range like the call that created it
+func (r *LoxRange) String() string {
+	return fmt.Sprintf("range(%s, %s, %s)", formatNumber(r.Start), formatNumber(r.End), formatNumber(r.Step))
+}
+
+type rangeIterator struct {
+	current interface{}
+	end     interface{}
+	step    interface{}
+}
+
+var plus = def.Token{Type: def.PLUS, Lexeme: "+"}
+
+func (it *rangeIterator) Next(i *Interpreter) (interface{}, bool, *def.RuntimeError) {
+	position, comparable := compareNumbers(it.current, it.end)
+	direction, _ := compareNumbers(it.step, int64(0))
+	if !comparable || position*direction >= 0 {
+		return nil, false, nil
+	}
+	value := it.current
+	it.current, _ = arithmetic(plus, it.current, it.step)
+	return value, true, nil
+}
+
+// listIterator checks the size on each step, so values pushed inside the loop are visited
+type listIterator struct {
+	list *LoxList
+	idx  int
+}
+
+func (it *listIterator) Next(i *Interpreter) (interface{}, bool, *def.RuntimeError) {
+	if it.idx >= len(it.list.Elements) {
+		return nil, false, nil
+	}
+	value := it.list.Elements[it.idx]
+	it.idx++
+	return value, true, nil
+}
+
+// sliceIterator walks a snapshot, used for tuples, map keys and string characters
+type sliceIterator struct {
+	values []interface{}
+	idx    int
+}
+
+func (it *sliceIterator) Next(i *Interpreter) (interface{}, bool, *def.RuntimeError) {
+	if it.idx >= len(it.values) {
+		return nil, false, nil
+	}
+	value := it.values[it.idx]
+	it.idx++
+	return value, true, nil
+}
+
+// instanceIterator calls next() on a user-defined iterator, nil means it is over
+type instanceIterator struct {
+	next Callable
+}
+
+func (it *instanceIterator) Next(i *Interpreter) (interface{}, bool, *def.RuntimeError) {
+	value, err := it.next.Call(i, []interface{}{})
+	if err != nil {
+		return nil, false, err
+	}
+	return value, value != nil, nil
+}
+
+// iteratorFor finds how to walk a value, instances can implement iterator() and next()
+func (i *Interpreter) iteratorFor(token def.Token, value interface{}) (Iterator, *def.RuntimeError) {
+	switch iterable := value.(type) {
+	case Iterator:
+		return iterable, nil
+	case *LoxRange:
+		return &rangeIterator{current: iterable.Start, end: iterable.End, step: iterable.Step}, nil
+	case *LoxList:
+		return &listIterator{list: iterable}, nil
+	case *LoxTuple:
+		return &sliceIterator{values: iterable.Elements}, nil
+	case *LoxMap:
+		keys := make([]interface{}, len(iterable.Keys))
+		copy(keys, iterable.Keys)
+		return &sliceIterator{values: keys}, nil
+	case string:
+		characters := []interface{}{}
+		for _, character := range iterable {
+			characters = append(characters, string(character))
+		}
+		return &sliceIterator{values: characters}, nil
+	case *LoxInstance:
+		if _, ok := iterable.Class.FindMethod("iterator"); ok {
+			iterator, err := i.callMethod(iterable, "iterator", token)
+			if err != nil {
+				return nil, err
+			}
+			if iterator != value {
+				return i.iteratorFor(token, iterator)
+			}
+		}
+		next, err := iterable.Get(def.Token{Type: def.IDENTIFIER, Lexeme: "next", Line: token.Line, File: token.File})
+		if err != nil {
+			break
+		}
+		if callable, ok := next.(Callable); ok {
+			return &instanceIterator{next: callable}, nil
+		}
+	}
+	return nil, &def.RuntimeError{
+		Token:   token,
+		Message: "Only lists, tuples, maps, strings, ranges and iterators can be iterated.",
+	}
+}
+
+// callMethod calls a method of an instance with the given arguments
+func (i *Interpreter) callMethod(instance *LoxInstance, name string, token def.Token, args ...interface{}) (interface{}, *def.RuntimeError) {
+	method, _ := instance.Class.FindMethod(name)
+	value, err := method.Bind(instance).Call(i, args)
+	if err != nil && err.Token.Line == 0 {
+		err.Token = token
+	}
+	return value, err
+}
@@ -0,0 +1,322 @@
+package runtime
+
+import (
+	"fmt"
+	"loxlang/parser/def"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Numbers are int64 while they fit, *big.Int when they overflow and float64 once a float is involved.
+// Big results that fit again are always given back as int64, so equal integers have one representation
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int64, *big.Int, float64:
+		return true
+	}
+	return false
+}
+
+func isInteger(value interface{}) bool {
+	switch value.(type) {
+	case int64, *big.Int:
+		return true
+	}
+	return false
+}
+
+// normalize gives back a big result as int64 when it fits
+func normalize(value *big.Int) interface{} {
+	if value.IsInt64() {
+		return value.Int64()
+	}
+	return value
+}
+
+func toBig(value interface{}) *big.Int {
+	switch number := value.(type) {
+	case int64:
+		return big.NewInt(number)
+	case *big.Int:
+		return number
+	}
+	return nil
+}
+
+func toFloat(value interface{}) float64 {
+	switch number := value.(type) {
+	case int64:
+		return float64(number)
+	case *big.Int:
+		result, _ := new(big.Float).SetInt(number).Float64()
+		return result
+	case float64:
+		return number
+	}
+	return 0
+}
+
+// integerValue gives the value of an integer, or of a float without fraction, that fits in an int64
+func integerValue(value interface{}) (int64, bool) {
+	switch number := value.(type) {
+	case int64:
+		return number, true
+	case float64:
+		if number == math.Trunc(number) && number >= math.MinInt64 && number < math.MaxInt64 {
+			return int64(number), true
+		}
+	}
+	return 0, false
+}
+
+// compareNumbers compares exactly, even between kinds, ok is false when NaN is involved
+func compareNumbers(left interface{}, right interface{}) (int, bool) {
+	leftInt, leftIsInt := left.(int64)
+	rightInt, rightIsInt := right.(int64)
+	if leftIsInt && rightIsInt {
+		switch {
+		case leftInt < rightInt:
+			return -1, true
+		case leftInt > rightInt:
+			return 1, true
+		}
+		return 0, true
+	}
+	if isInteger(left) && isInteger(right) {
+		return toBig(left).Cmp(toBig(right)), true
+	}
+	if math.IsNaN(toFloat(left)) || math.IsNaN(toFloat(right)) {
+		return 0, false
+	}
+	return exactFloat(left).Cmp(exactFloat(right)), true
+}
+
+func exactFloat(value interface{}) *big.Float {
+	if isInteger(value) {
+		return new(big.Float).SetInt(toBig(value))
+	}
+	return new(big.Float).SetFloat64(value.(float64))
+}
+
+// arithmetic runs + - * / % ~/ ** on numbers, ints only become floats when mixed with them or on /
+func arithmetic(operator def.Token, left interface{}, right interface{}) (interface{}, *def.RuntimeError) {
+	if isInteger(left) && isInteger(right) {
+		return integerArithmetic(operator, left, right)
+	}
+	leftVal, rightVal := toFloat(left), toFloat(right)
+	switch operator.Type {
+	case def.PLUS:
+		return leftVal + rightVal, nil
+	case def.MINUS:
+		return leftVal - rightVal, nil
+	case def.STAR:
+		return leftVal * rightVal, nil
+	case def.SLASH:
+		if rightVal == 0.0 {
+			return nil, divideByZero(operator)
+		}
+		return leftVal / rightVal, nil
+	case def.PERCENT:
+		if rightVal == 0.0 {
+			return nil, remainderByZero(operator)
+		}
+		return math.Mod(leftVal, rightVal), nil
+	case def.TILDESLASH:
+		if rightVal == 0.0 {
+			return nil, divideByZero(operator)
+		}
+		return math.Floor(leftVal / rightVal), nil
+	case def.STARSTAR:
+		return math.Pow(leftVal, rightVal), nil
+	}
+	return nil, nil
+}
+
+func integerArithmetic(operator def.Token, left interface{}, right interface{}) (interface{}, *def.RuntimeError) {
+	leftInt, leftIsInt := left.(int64)
+	rightInt, rightIsInt := right.(int64)
+	if leftIsInt && rightIsInt {
+		// the common case, big.Int is only used when the result doesn't fit
+		switch operator.Type {
+		case def.PLUS:
+			sum := leftInt + rightInt
+			if (sum > leftInt) == (rightInt > 0) {
+				return sum, nil
+			}
+		case def.MINUS:
+			difference := leftInt - rightInt
+			if (difference < leftInt) == (rightInt > 0) {
+				return difference, nil
+			}
+		case def.STAR:
+			if leftInt == 0 || rightInt == 0 {
+				return int64(0), nil
+			}
+			product := leftInt * rightInt
+			if product/rightInt == leftInt && !(leftInt == -1 && rightInt == math.MinInt64) && !(rightInt == -1 && leftInt == math.MinInt64) {
+				return product, nil
+			}
+		}
+	}
+
+	leftVal, rightVal := toBig(left), toBig(right)
+	switch operator.Type {
+	case def.PLUS:
+		return normalize(new(big.Int).Add(leftVal, rightVal)), nil
+	case def.MINUS:
+		return normalize(new(big.Int).Sub(leftVal, rightVal)), nil
+	case def.STAR:
+		return normalize(new(big.Int).Mul(leftVal, rightVal)), nil
+	case def.SLASH:
+		if rightVal.Sign() == 0 {
+			return nil, divideByZero(operator)
+		}
+		return toFloat(left) / toFloat(right), nil
+	case def.PERCENT:
+		if rightVal.Sign() == 0 {
+			return nil, remainderByZero(operator)
+		}
+		// truncated like math.Mod, the result has the sign of the left operand
+		return normalize(new(big.Int).Rem(leftVal, rightVal)), nil
+	case def.TILDESLASH:
+		if rightVal.Sign() == 0 {
+			return nil, divideByZero(operator)
+		}
+		quotient, modulus := new(big.Int).QuoRem(leftVal, rightVal, new(big.Int))
+		if modulus.Sign() != 0 && (modulus.Sign() < 0) != (rightVal.Sign() < 0) {
+			quotient.Sub(quotient, big.NewInt(1))
+		}
+		return normalize(quotient), nil
+	case def.STARSTAR:
+		if rightVal.Sign() < 0 {
+			return math.Pow(toFloat(left), toFloat(right)), nil
+		}
+		if !rightVal.IsInt64() || rightVal.Int64() > 1<<20 {
+			return nil, &def.RuntimeError{
+				Token:   operator,
+				Message: "Exponent is too big",
+			}
+		}
+		return normalize(new(big.Int).Exp(leftVal, rightVal, nil)), nil
+	}
+	return nil, nil
+}
+
+func negate(value interface{}) interface{} {
+	switch number := value.(type) {
+	case int64:
+		if number == math.MinInt64 {
+			return new(big.Int).Neg(big.NewInt(number))
+		}
+		return -number
+	case *big.Int:
+		return normalize(new(big.Int).Neg(number))
+	}
+	return -value.(float64)
+}
+
+func divideByZero(operator def.Token) *def.RuntimeError {
+	return &def.RuntimeError{
+		Token:   operator,
+		Message: "Can't divide by 0",
+	}
+}
+
+func remainderByZero(operator def.Token) *def.RuntimeError {
+	return &def.RuntimeError{
+		Token:   operator,
+		Message: "Can't take the remainder of a division by 0",
+	}
+}
+
+// bitOperand takes integers, and floats without fraction, for the bitwise operators
+func bitOperand(value interface{}) (*big.Int, bool) {
+	if isInteger(value) {
+		return toBig(value), true
+	}
+	if integer, ok := integerValue(value); ok {
+		return big.NewInt(integer), true
+	}
+	return nil, false
+}
+
+func formatNumber(value interface{}) string {
+	switch number := value.(type) {
+	case int64:
+		return strconv.FormatInt(number, 10)
+	case *big.Int:
+		return number.String()
+	}
+	// the shortest text that reads back as the same float, whole floats show no fraction
+	// unless they are big enough to need an exponent
+	float := value.(float64)
+	if float == math.Trunc(float) && math.Abs(float) < 1e21 {
+		return strconv.FormatFloat(float, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(float, 'g', -1, 64)
+}
+
+// mapKey gives equal numbers the same key, whatever their kind
+func mapKey(key interface{}) interface{} {
+	switch number := key.(type) {
+	case float64:
+		if integer, ok := integerValue(number); ok {
+			return integer
+		}
+	case *big.Int:
+		return bigKey(number.String())
+	}
+	return key
+}
+
+// bigKey stands for a *big.Int in map keys, pointers wouldn't compare by value
+type bigKey string
+
+// toInteger converts for int(), floats are truncated toward zero and strings are parsed
+func toInteger(value interface{}) (interface{}, *def.RuntimeError) {
+	switch number := value.(type) {
+	case int64, *big.Int:
+		return number, nil
+	case float64:
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, &def.RuntimeError{
+				Message: fmt.Sprintf("Can't convert %s to an integer", formatNumber(number)),
+			}
+		}
+		truncated, _ := big.NewFloat(math.Trunc(number)).Int(nil)
+		return normalize(truncated), nil
+	case string:
+		parsed, ok := new(big.Int).SetString(strings.ReplaceAll(strings.TrimSpace(number), "_", ""), 10)
+		if !ok {
+			return nil, &def.RuntimeError{
+				Message: fmt.Sprintf("Can't convert \"%s\" to an integer", number),
+			}
+		}
+		return normalize(parsed), nil
+	}
+	return nil, &def.RuntimeError{
+		Message: "int() expects a number or a string",
+	}
+}
+
+// toFloatValue converts for float(), strings are parsed
+func toFloatValue(value interface{}) (interface{}, *def.RuntimeError) {
+	if isNumber(value) {
+		return toFloat(value), nil
+	}
+	if text, ok := value.(string); ok {
+		parsed, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(text), "_", ""), 64)
+		if err != nil {
+			return nil, &def.RuntimeError{
+				Message: fmt.Sprintf("Can't convert \"%s\" to a float", text),
+			}
+		}
+		return parsed, nil
+	}
+	return nil, &def.RuntimeError{
+		Message: "float() expects a number or a string",
+	}
+}
@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"fmt"
+	"loxlang/parser/def"
+)
+
+// Instances can implement operators with methods, like __add__ for +.
+// The left operand is asked first, then the reflected method of the right one: __radd__ for
+// arithmetic, so 2 * vec works, and the mirrored comparison, since a < b is b > a
+
+var binaryMethods = map[def.TokenType]string{
+	def.PLUS:         "__add__",
+	def.MINUS:        "__sub__",
+	def.STAR:         "__mul__",
+	def.SLASH:        "__div__",
+	def.PERCENT:      "__mod__",
+	def.TILDESLASH:   "__floordiv__",
+	def.STARSTAR:     "__pow__",
+	def.LESS:         "__lt__",
+	def.LESSEQUAL:    "__le__",
+	def.GREATER:      "__gt__",
+	def.GREATEREQUAL: "__ge__",
+}
+
+// reflectedMethods answer from the right operand, called with the left one
+var reflectedMethods = map[def.TokenType]string{
+	def.PLUS:         "__radd__",
+	def.MINUS:        "__rsub__",
+	def.STAR:         "__rmul__",
+	def.SLASH:        "__rdiv__",
+	def.PERCENT:      "__rmod__",
+	def.TILDESLASH:   "__rfloordiv__",
+	def.STARSTAR:     "__rpow__",
+	def.LESS:         "__gt__",
+	def.LESSEQUAL:    "__ge__",
+	def.GREATER:      "__lt__",
+	def.GREATEREQUAL: "__le__",
+}
+
+// overloadedBinary runs the operator method of an instance operand, handled is false when there is none
+func (i *Interpreter) overloadedBinary(operator def.Token, left interface{}, right interface{}) (value interface{}, handled bool, err *def.RuntimeError) {
+	if operator.Type == def.EQUALEQUAL || operator.Type == def.BANGEQUAL {
+		equal, handled, err := i.overloadedEquality(operator, left, right)
+		if !handled || err != nil {
+			return nil, handled, err
+		}
+		return equal == (operator.Type == def.EQUALEQUAL), true, nil
+	}
+	name, ok := binaryMethods[operator.Type]
+	if !ok {
+		return nil, false, nil
+	}
+	if instance, isInstance := left.(*LoxInstance); isInstance {
+		if _, found := instance.Class.FindMethod(name); found {
+			value, err := i.callOperator(instance, name, operator, right)
+			if err != nil {
+				return nil, true, err
+			}
+			return i.operatorResult(operator, name, value)
+		}
+	}
+	reflected := reflectedMethods[operator.Type]
+	if instance, isInstance := right.(*LoxInstance); isInstance {
+		if _, found := instance.Class.FindMethod(reflected); found {
+			value, err := i.callOperator(instance, reflected, operator, left)
+			if err != nil {
+				return nil, true, err
+			}
+			return i.operatorResult(operator, reflected, value)
+		}
+	}
+	return nil, false, nil
+}
+
+// overloadedEquality asks __eq__ of the left operand, then of the right one
+func (i *Interpreter) overloadedEquality(operator def.Token, left interface{}, right interface{}) (equal bool, handled bool, err *def.RuntimeError) {
+	instance, other := left, right
+	for attempt := 0; attempt < 2; attempt++ {
+		if candidate, isInstance := instance.(*LoxInstance); isInstance {
+			if _, found := candidate.Class.FindMethod("__eq__"); found {
+				value, err := i.callOperator(candidate, "__eq__", operator, other)
+				if err != nil {
+					return false, true, err
+				}
+				result, isBool := value.(bool)
+				if !isBool {
+					return false, true, notABoolean(operator, "__eq__")
+				}
+				return result, true, nil
+			}
+		}
+		instance, other = right, left
+	}
+	return false, false, nil
+}
+
+// operatorResult checks that comparison methods answered with a boolean
+func (i *Interpreter) operatorResult(operator def.Token, name string, value interface{}) (interface{}, bool, *def.RuntimeError) {
+	switch operator.Type {
+	case def.LESS, def.LESSEQUAL, def.GREATER, def.GREATEREQUAL:
+		if _, isBool := value.(bool); !isBool {
+			return nil, true, notABoolean(operator, name)
+		}
+	}
+	return value, true, nil
+}
+
+// overloadedUnary runs __neg__ for -, handled is false when the operand doesn't implement it
+func (i *Interpreter) overloadedUnary(operator def.Token, operand interface{}) (interface{}, bool, *def.RuntimeError) {
+	instance, isInstance := operand.(*LoxInstance)
+	if !isInstance || operator.Type != def.MINUS {
+		return nil, false, nil
+	}
+	if _, found := instance.Class.FindMethod("__neg__"); !found {
+		return nil, false, nil
+	}
+	value, err := i.callOperator(instance, "__neg__", operator)
+	return value, true, err
+}
+
+// callOperator calls an operator method, checking first that it takes the operands
+func (i *Interpreter) callOperator(instance *LoxInstance, name string, token def.Token, args ...interface{}) (interface{}, *def.RuntimeError) {
+	method, _ := instance.Class.FindMethod(name)
+	if !method.Arity().Accepts(len(args)) {
+		operands := "one operand"
+		if len(args) == 0 {
+			operands = "no operands"
+		}
+		return nil, &def.RuntimeError{
+			Token:   token,
+			Message: fmt.Sprintf("%s must take %s, but takes %s arguments", name, operands, method.Arity()),
+		}
+	}
+	return i.callMethod(instance, name, token, args...)
+}
+
+func notABoolean(operator def.Token, name string) *def.RuntimeError {
+	return &def.RuntimeError{
+		Token:   operator,
+		Message: fmt.Sprintf("%s must return a boolean", name),
+	}
+}
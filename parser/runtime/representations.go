@@ -7,15 +7,67 @@ import (
 
 // Callable is a function-call representation in runtime
 type Callable interface {
-	Arity() int
+	Arity() Arity
 	Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError)
 }
 
+// NamedCallable is a Callable whose parameters can also be passed by name
+type NamedCallable interface {
+	Callable
+	ParamNames() []string
+}
+
+// Variadic is the Max of an Arity without an upper limit
+const Variadic = -1
+
+// Arity is the range of arguments a callable accepts
+type Arity struct {
+	Min int
+	Max int
+}
+
+// FixedArity is the Arity of a callable that always takes n arguments
+func FixedArity(n int) Arity {
+	return Arity{Min: n, Max: n}
+}
+
+// Accepts checks if count arguments are in the range
+func (a Arity) Accepts(count int) bool {
+	return count >= a.Min && (a.Max == Variadic || count <= a.Max)
+}
+
+// String describes the range, used in mismatch messages
+func (a Arity) String() string {
+	if a.Max == Variadic {
+		return fmt.Sprintf("at least %d", a.Min)
+	}
+	if a.Min == a.Max {
+		return fmt.Sprintf("%d", a.Min)
+	}
+	return fmt.Sprintf("%d to %d", a.Min, a.Max)
+}
+
+// missingArgument fills the place of an optional param skipped by named arguments
+type missingArgument struct{}
+
+// Missing is passed to Call for optional params that were not given
+var Missing = &missingArgument{}
+
+// argumentAt returns the argument for a param, if it was given
+func argumentAt(args []interface{}, idx int) (interface{}, bool) {
+	if idx >= len(args) || args[idx] == Missing {
+		return nil, false
+	}
+	return args[idx], true
+}
+
 // CallableFunction is a concrete representation of a user-defined function to be called
 type CallableFunction struct {
-	Name         string
-	FunctionExpr def.FunctionExpr
-	Closure      *Environment
+	Name          string
+	FunctionExpr  def.FunctionExpr
+	Closure       *Environment
+	Globals       map[string]interface{}
+	IsInitializer bool
 }
 
 // String counts how many parameters there are in a function
@@ -26,28 +78,336 @@ func (f *CallableFunction) String() string {
 	return "<fn>"
 }
 
-// Arity counts how many parameters there are in a function
-func (f *CallableFunction) Arity() int {
-	return len(f.FunctionExpr.Params)
+// Arity goes from the required params up to all of them, without limit when there is a rest param
+func (f *CallableFunction) Arity() Arity {
+	arity := Arity{Min: 0, Max: len(f.FunctionExpr.Params)}
+	for _, defaultValue := range f.FunctionExpr.Defaults {
+		if defaultValue == nil {
+			arity.Min++
+		}
+	}
+	if f.FunctionExpr.Rest != nil {
+		arity.Max = Variadic
+	}
+	return arity
+}
+
+// ParamNames are the names the params can be passed by
+func (f *CallableFunction) ParamNames() []string {
+	names := make([]string, len(f.FunctionExpr.Params))
+	for idx, param := range f.FunctionExpr.Params {
+		names[idx] = param.Lexeme
+	}
+	return names
 }
 
 // Call invoked the function
 func (f *CallableFunction) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	// functions always see the globals of the module that declared them
+	previousGlobals := i.Globals
+	i.Globals = f.Globals
+	defer func() { i.Globals = previousGlobals }()
+
 	localEnv := NewEnvironment(f.Closure)
-	for i := range f.FunctionExpr.Params {
-		(*localEnv).Define(args[i])
+	for idx := range f.FunctionExpr.Params {
+		arg, ok := argumentAt(args, idx)
+		if !ok {
+			// defaults are evaluated on each call, seeing the params before them
+			value, defaultErr := i.evaluateIn(f.FunctionExpr.Defaults[idx], localEnv)
+			if defaultErr != nil {
+				return nil, defaultErr
+			}
+			arg = value
+		}
+		(*localEnv).Define(arg)
+	}
+	if f.FunctionExpr.Rest != nil {
+		rest := []interface{}{}
+		if len(args) > len(f.FunctionExpr.Params) {
+			rest = append(rest, args[len(f.FunctionExpr.Params):]...)
+		}
+		(*localEnv).Define(&LoxList{Elements: rest})
+	}
+	if f.FunctionExpr.IsGenerator {
+		// the body only starts running when the first value is asked
+		return NewGenerator(i, f, localEnv), nil
 	}
 	err := i.executeBlock(f.FunctionExpr.Body, localEnv)
 	if err != nil {
-		if err.Type == def.RETURNSTMT {
+		if err.Type != def.RETURNSTMT {
+			return nil, err
+		}
+		if !f.IsInitializer {
 			return err.Value, nil
 		}
-		return nil, err
+	}
+	if f.IsInitializer {
+		return f.Closure.GetAt(0, 0)
 	}
 	return nil, nil
 }
 
+// Bind creates a copy of the method with 'this' bound to the instance
+func (f *CallableFunction) Bind(instance *LoxInstance) *CallableFunction {
+	env := NewEnvironment(f.Closure)
+	env.Define(instance)
+	return &CallableFunction{
+		Name:          f.Name,
+		FunctionExpr:  f.FunctionExpr,
+		Closure:       env,
+		Globals:       f.Globals,
+		IsInitializer: f.IsInitializer,
+	}
+}
+
+// LoxClass is the runtime representation of a class
+type LoxClass struct {
+	Name       string
+	Superclass *LoxClass
+	Methods    map[string]*CallableFunction
+}
+
+// String shows the class name
+func (c *LoxClass) String() string {
+	return c.Name
+}
+
+// FindMethod looks up a method by name, walking up the superclass chain
+func (c *LoxClass) FindMethod(name string) (*CallableFunction, bool) {
+	if method, ok := c.Methods[name]; ok {
+		return method, true
+	}
+	if c.Superclass != nil {
+		return c.Superclass.FindMethod(name)
+	}
+	return nil, false
+}
+
+// Arity is the same as the init method, if there is one
+func (c *LoxClass) Arity() Arity {
+	if initializer, ok := c.FindMethod("init"); ok {
+		return initializer.Arity()
+	}
+	return FixedArity(0)
+}
+
+// ParamNames are the same as the init method, if there is one
+func (c *LoxClass) ParamNames() []string {
+	if initializer, ok := c.FindMethod("init"); ok {
+		return initializer.ParamNames()
+	}
+	return nil
+}
+
+// Call creates a new instance of the class, running init if present
+func (c *LoxClass) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	instance := &LoxInstance{
+		Class:  c,
+		Fields: map[string]interface{}{},
+	}
+	if initializer, ok := c.FindMethod("init"); ok {
+		_, err := initializer.Bind(instance).Call(i, args)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return instance, nil
+}
+
+// ErrorClass is the class of the values that catch receives for runtime errors, and of Error(message)
+var ErrorClass = &LoxClass{Name: "Error", Methods: map[string]*CallableFunction{}}
+
+// NewErrorInstance creates an Error value, line is nil until it is thrown
+func NewErrorInstance(message string, line interface{}) *LoxInstance {
+	return &LoxInstance{
+		Class: ErrorClass,
+		Fields: map[string]interface{}{
+			"message": message,
+			"line":    line,
+		},
+	}
+}
+
+// LoxInstance is the runtime representation of an object
+type LoxInstance struct {
+	Class  *LoxClass
+	Fields map[string]interface{}
+}
+
+// String shows the instance class name
+func (instance *LoxInstance) String() string {
+	return instance.Class.Name + " instance"
+}
+
+// Get returns a field or a bound method of the instance
+func (instance *LoxInstance) Get(name def.Token) (interface{}, *def.RuntimeError) {
+	if value, ok := instance.Fields[name.Lexeme]; ok {
+		return value, nil
+	}
+	if method, ok := instance.Class.FindMethod(name.Lexeme); ok {
+		return method.Bind(instance), nil
+	}
+	return nil, &def.RuntimeError{
+		Token:   name,
+		Message: fmt.Sprintf("Undefined property %s.", name.Lexeme),
+	}
+}
+
+// Set assigns a value to a field of the instance
+func (instance *LoxInstance) Set(name def.Token, value interface{}) {
+	instance.Fields[name.Lexeme] = value
+}
+
 // ReturnValue represents the value that returns from a function
 type ReturnValue struct {
 	Value interface{}
 }
+
+// LoxList is the runtime representation of a list
+type LoxList struct {
+	Elements []interface{}
+}
+
+// LoxEnum is the runtime representation of an enum, a namespace for its members
+type LoxEnum struct {
+	Name    string
+	Members []*LoxEnumMember
+}
+
+// String shows the enum name
+func (e *LoxEnum) String() string {
+	return e.Name
+}
+
+// Get returns a member of the enum, or its values method
+func (e *LoxEnum) Get(name def.Token) (interface{}, *def.RuntimeError) {
+	for _, member := range e.Members {
+		if member.Name == name.Lexeme {
+			return member, nil
+		}
+	}
+	if name.Lexeme == "values" {
+		return &EnumValuesCallable{Enum: e}, nil
+	}
+	return nil, &def.RuntimeError{
+		Token:   name,
+		Message: fmt.Sprintf("Undefined enum member %s.%s.", e.Name, name.Lexeme),
+	}
+}
+
+// LoxEnumMember is a value of an enum, there is only one of each so they compare by identity
+type LoxEnumMember struct {
+	Enum    *LoxEnum
+	Name    string
+	Ordinal int64
+}
+
+// String shows the member with its enum, like Color.Red
+func (m *LoxEnumMember) String() string {
+	return m.Enum.Name + "." + m.Name
+}
+
+// Get returns the name or the ordinal of the member
+func (m *LoxEnumMember) Get(name def.Token) (interface{}, *def.RuntimeError) {
+	switch name.Lexeme {
+	case "name":
+		return m.Name, nil
+	case "ordinal":
+		return m.Ordinal, nil
+	}
+	return nil, &def.RuntimeError{
+		Token:   name,
+		Message: fmt.Sprintf("Undefined property %s.", name.Lexeme),
+	}
+}
+
+// EnumValuesCallable lists the members of an enum, in declaration order
+type EnumValuesCallable struct {
+	Enum *LoxEnum
+}
+
+// Arity of the values fn
+func (c *EnumValuesCallable) Arity() Arity {
+	return FixedArity(0)
+}
+
+// Call representation of the values fn
+func (c *EnumValuesCallable) Call(i *Interpreter, args []interface{}) (interface{}, *def.RuntimeError) {
+	members := make([]interface{}, len(c.Enum.Members))
+	for idx, member := range c.Enum.Members {
+		members[idx] = member
+	}
+	return &LoxList{Elements: members}, nil
+}
+
+// LoxTuple is the runtime representation of a tuple, the fixed group of values of return a, b;
+type LoxTuple struct {
+	Elements []interface{}
+}
+
+// LoxMap is the runtime representation of a map, keys are kept in insertion order.
+// Entries are stored under mapKey, so 1 and 1.0 are the same key
+type LoxMap struct {
+	Keys    []interface{}
+	Entries map[interface{}]interface{}
+}
+
+// NewLoxMap creates an empty map
+func NewLoxMap() *LoxMap {
+	return &LoxMap{
+		Keys:    []interface{}{},
+		Entries: map[interface{}]interface{}{},
+	}
+}
+
+// Get returns the value stored in key
+func (m *LoxMap) Get(key interface{}) (interface{}, bool) {
+	value, ok := m.Entries[mapKey(key)]
+	return value, ok
+}
+
+// Set stores value in key
+func (m *LoxMap) Set(key interface{}, value interface{}) {
+	if _, ok := m.Entries[mapKey(key)]; !ok {
+		m.Keys = append(m.Keys, key)
+	}
+	m.Entries[mapKey(key)] = value
+}
+
+// Delete removes key from the map, returning if it was present
+func (m *LoxMap) Delete(key interface{}) bool {
+	if _, ok := m.Entries[mapKey(key)]; !ok {
+		return false
+	}
+	delete(m.Entries, mapKey(key))
+	for idx, k := range m.Keys {
+		if mapKey(k) == mapKey(key) {
+			m.Keys = append(m.Keys[:idx], m.Keys[idx+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// LoxModule is the runtime representation of an imported file. Globals are the live globals
+// of its interpreter, so the importer sees the values the module assigns later
+type LoxModule struct {
+	Name    string
+	Globals map[string]interface{}
+}
+
+// String shows the module name
+func (m *LoxModule) String() string {
+	return fmt.Sprintf("<module %s>", m.Name)
+}
+
+// Get returns an exported member of the module
+func (m *LoxModule) Get(name def.Token) (interface{}, *def.RuntimeError) {
+	if value, ok := m.Globals[name.Lexeme]; ok && isExported(name.Lexeme, value) {
+		return value, nil
+	}
+	return nil, &def.RuntimeError{
+		Token:   name,
+		Message: fmt.Sprintf("Module %s has no member %s.", m.Name, name.Lexeme),
+	}
+}